@@ -3,25 +3,31 @@ package benchmarker
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog/log"
 	"github.com/unifralabs/unifra-benchmark-tool/config"
 	"github.com/unifralabs/unifra-benchmark-tool/db"
+	"github.com/unifralabs/unifra-benchmark-tool/outputter"
 	"github.com/unifralabs/unifra-benchmark-tool/rpc_client"
+	"github.com/unifralabs/unifra-benchmark-tool/stats"
 	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
 	"github.com/unifralabs/unifra-benchmark-tool/utils"
 )
 
 type Benchmarker struct {
-	cfg              *config.EnvConfig
-	client           *ethclient.Client
-	rpcClient        *rpc_client.RpcClient
-	dbClient         *db.Client
-	node             tooltypes.Node
-	nodes            tooltypes.Nodes
-	eoaTxBenchmarker *TxBenchmarker
-	rpcBenchmarker   *RpcBenchmarker
+	cfg            *config.EnvConfig
+	client         *ethclient.Client
+	rpcClient      *rpc_client.RpcClient
+	dbClient       *db.Client
+	node           tooltypes.Node
+	nodes          tooltypes.Nodes
+	txTypes        []tooltypes.TxType
+	txBenchmarkers []*TxBenchmarker
+	rpcBenchmarker *RpcBenchmarker
 }
 
 func NewBenchmarker(cfg *config.EnvConfig) (*Benchmarker, error) {
@@ -40,53 +46,167 @@ func NewBenchmarker(cfg *config.EnvConfig) (*Benchmarker, error) {
 		return nil, fmt.Errorf("error creating db client: %s", err)
 	}
 
-	nodeStr := cfg.NodeName + "=" + cfg.RpcUrl
-	node, err := utils.ParseNode(nodeStr, true)
+	nodes, err := NodesFromConfig(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing node: %s", err)
+		return nil, err
 	}
-	nodes := tooltypes.Nodes{node.Name: node}
+	node := nodes[sortedNodeNames(nodes)[0]]
 	// log.Info().Msgf("node: %s", node)
 
-	eoaTxBenchmarker, err := NewTxBenchmarker(client, cfg.AdminAccountMnemonic, cfg.RpcUrl, tooltypes.EOA, cfg.NumTestAccounts, 60,
-		cfg.SendTransactionBatchSize, cfg.OutputDir)
+	feeMode, err := tooltypes.ParseFeeMode(cfg.TxFeeMode)
+	if err != nil {
+		return nil, err
+	}
+
+	txTypes, err := ParseTxTypes(cfg.TxTypes)
 	if err != nil {
 		return nil, err
 	}
+	txNode, err := TxNodeFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	txBenchmarkers := make([]*TxBenchmarker, 0, len(txTypes))
+	for _, txType := range txTypes {
+		options := TxOptionsFromConfig(cfg, txType, feeMode)
+		options.Contracts = dbClient
+		options.History = dbClient
+		txBenchmarker, err := NewTxBenchmarker(client, cfg.AdminAccountMnemonic, txNode, txType, options,
+			cfg.NumTestAccounts, cfg.NumTransactions, cfg.SendTransactionBatchSize, cfg.OutputDir)
+		if err != nil {
+			return nil, fmt.Errorf("error creating %s benchmarker: %s", txType, err)
+		}
+		txBenchmarkers = append(txBenchmarkers, txBenchmarker)
+	}
 
-	rpcBenchmarker, err := NewRpcBenchmarker(cfg, nodes)
+	rpcBenchmarker, err := NewRpcBenchmarker(cfg, nodes, dbClient)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Benchmarker{
-		cfg:              cfg,
-		client:           client,
-		rpcClient:        rpcClient,
-		dbClient:         dbClient,
-		node:             node,
-		nodes:            nodes,
-		eoaTxBenchmarker: eoaTxBenchmarker,
-		rpcBenchmarker:   rpcBenchmarker,
+		cfg:            cfg,
+		client:         client,
+		rpcClient:      rpcClient,
+		dbClient:       dbClient,
+		node:           node,
+		nodes:          nodes,
+		txTypes:        txTypes,
+		txBenchmarkers: txBenchmarkers,
+		rpcBenchmarker: rpcBenchmarker,
 	}, nil
 }
 
-func (b *Benchmarker) Initialize() error {
-	err := b.eoaTxBenchmarker.Initialize()
+// NodesFromConfig returns the nodes under test described by the config:
+// the NODES list, or else the single node of NODE_NAME and RPC_URL
+func NodesFromConfig(cfg *config.EnvConfig) (tooltypes.Nodes, error) {
+	if cfg.Nodes != "" {
+		specs := []string{}
+		for _, spec := range strings.Split(cfg.Nodes, ",") {
+			if spec = strings.TrimSpace(spec); spec != "" {
+				specs = append(specs, spec)
+			}
+		}
+		nodes, err := utils.ParseNodes(specs, false, true)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing nodes: %s", err)
+		}
+		if len(nodes) != len(specs) {
+			return nil, fmt.Errorf("error parsing nodes: %d nodes for %d specs, check for duplicate names", len(nodes), len(specs))
+		}
+		return nodes, nil
+	}
+
+	nodeStr := cfg.NodeName + "=" + cfg.RpcUrl
+	node, err := utils.ParseNode(nodeStr, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing node: %s", err)
+	}
+	return tooltypes.Nodes{node.Name: node}, nil
+}
+
+// TxNodeFromConfig returns the node that the transaction benchmarks send to:
+// RPC_URL, named after NODE_NAME or else after the host of the URL
+func TxNodeFromConfig(cfg *config.EnvConfig) (tooltypes.Node, error) {
+	name := cfg.NodeName
+	if name == "" {
+		parsed, err := url.Parse(cfg.RpcUrl)
+		if err != nil || parsed.Host == "" {
+			return tooltypes.Node{}, fmt.Errorf("invalid RPC URL: %s", cfg.RpcUrl)
+		}
+		name = parsed.Host
+	}
+
+	// Name the spec, so that an = in the URL is not taken for the name separator
+	node, err := utils.ParseNode(name+"="+cfg.RpcUrl, true)
 	if err != nil {
-		return err
+		return tooltypes.Node{}, fmt.Errorf("error parsing node: %s", err)
 	}
-	return nil
+	return node, nil
 }
 
+// ParseTxTypes parses a comma-separated list of transaction types, e.g. "eoa,erc20"
+func ParseTxTypes(spec string) ([]tooltypes.TxType, error) {
+	txTypes := []tooltypes.TxType{}
+	for _, s := range strings.Split(spec, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		txType, err := tooltypes.ParseTxType(s)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(txTypes, txType) {
+			return nil, fmt.Errorf("duplicate transaction type: %s", s)
+		}
+		txTypes = append(txTypes, txType)
+	}
+	return txTypes, nil
+}
+
+// TxOptionsFromConfig returns the options of the runtime transactions of a type
+func TxOptionsFromConfig(cfg *config.EnvConfig, txType tooltypes.TxType, feeMode tooltypes.FeeMode) tooltypes.TxOptions {
+	options := tooltypes.TxOptions{FeeMode: feeMode, BlobsPerTx: cfg.BlobsPerTx, GasPerTx: cfg.StressGasPerTx}
+	if txType == tooltypes.CONTRACT {
+		options.Contract = &tooltypes.ContractCallSpec{
+			ABI:             cfg.ContractAbi,
+			Bin:             cfg.ContractBin,
+			Address:         cfg.ContractAddress,
+			ConstructorArgs: utils.SplitArgs(cfg.ContractConstructorArgs),
+			Method:          cfg.ContractMethod,
+			Args:            utils.SplitArgs(cfg.ContractArgs),
+			Value:           cfg.ContractValue,
+		}
+	}
+	return options
+}
+
+// RunBenchmarks runs the transaction benchmarks in sequence, then the RPC
+// benchmark. Each transaction benchmark is initialized right before it runs,
+// so that the accounts are funded for its own transactions.
 func (b *Benchmarker) RunBenchmarks(ctx context.Context) {
+	results := make(map[tooltypes.TxType]*stats.CollectorData)
+	for i, txBenchmarker := range b.txBenchmarkers {
+		txType := b.txTypes[i]
+		log.Info().Msgf("Running %s benchmark (%d/%d)", txType, i+1, len(b.txBenchmarkers))
 
-	err := b.eoaTxBenchmarker.Run()
-	if err != nil {
-		log.Error().Msgf("Error occurred when running EOA benchmarker: %v", err)
+		if err := txBenchmarker.Initialize(); err != nil {
+			log.Error().Msgf("Error occurred when initializing %s benchmarker: %v", txType, err)
+			continue
+		}
+		data, err := txBenchmarker.Run()
+		if err != nil {
+			log.Error().Msgf("Error occurred when running %s benchmarker: %v", txType, err)
+			continue
+		}
+		results[txType] = data
+	}
+	if len(b.txBenchmarkers) > 1 {
+		outputter.PrintTxSummary(b.txTypes, results)
 	}
 
-	err = b.rpcBenchmarker.Run()
+	err := b.rpcBenchmarker.Run()
 	if err != nil {
 		log.Error().Msgf("Error occurred when running RPC benchmarker: %v", err)
 	}
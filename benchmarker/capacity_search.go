@@ -0,0 +1,181 @@
+package benchmarker
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+	"github.com/unifralabs/unifra-benchmark-tool/rpc_builder"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// SearchMaxThroughput finds, for every node, the highest rate that meets the
+// SLO. The rate doubles from the start rate until the SLO breaks, then a
+// binary search narrows the capacity down to the search precision.
+func SearchMaxThroughput(
+	nodes tooltypes.Nodes,
+	param tooltypes.TestGenerationParameters,
+	search tooltypes.CapacitySearchParameters,
+	verbose bool,
+	includeDeepOutput []tooltypes.DeepOutput,
+) (map[string]tooltypes.CapacityResult, error) {
+	if search.StartRate <= 0 || search.MaxRate < search.StartRate {
+		return nil, fmt.Errorf("capacity search requires 0 < start rate <= max rate")
+	}
+	if search.ProbeDuration <= 0 {
+		return nil, fmt.Errorf("capacity search requires a positive probe duration")
+	}
+	if search.Precision <= 0 {
+		search.Precision = 1
+	}
+
+	names := sortedNodeNames(nodes)
+
+	results := make(map[string]tooltypes.CapacityResult)
+	for _, name := range names {
+		node := nodes[name]
+		if verbose {
+			utils.PrintTimestamped(fmt.Sprintf("Searching max throughput of %s", node.Name))
+		}
+
+		result, err := searchNodeCapacity(node, param, search, verbose, includeDeepOutput)
+		if err != nil {
+			return nil, err
+		}
+		results[node.Name] = *result
+
+		log.Info().Msgf("%s sustains %d rps (success >= %g, p99 <= %gs)",
+			node.Name, result.Capacity, search.SLO.MinSuccess, search.SLO.MaxP99)
+	}
+
+	return results, nil
+}
+
+func searchNodeCapacity(
+	node tooltypes.Node,
+	param tooltypes.TestGenerationParameters,
+	search tooltypes.CapacitySearchParameters,
+	verbose bool,
+	includeDeepOutput []tooltypes.DeepOutput,
+) (*tooltypes.CapacityResult, error) {
+	result := &tooltypes.CapacityResult{Node: node.Name}
+
+	probe := func(rate int) (bool, error) {
+		p, err := probeRate(node, param, search, rate, verbose, includeDeepOutput)
+		if err != nil {
+			return false, err
+		}
+		result.Probes = append(result.Probes, *p)
+		if verbose {
+			log.Info().Msgf("probe %d rps: success %s, p99 %s, passed %t",
+				rate, formatOptionalFloat(p.Success), formatOptionalFloat(p.P99), p.Passed)
+		}
+		return p.Passed, nil
+	}
+
+	// Exponential phase: find a failing rate above the last passing one
+	passed, failed := 0, 0
+	for rate := search.StartRate; ; rate *= 2 {
+		if rate > search.MaxRate {
+			rate = search.MaxRate
+		}
+		ok, err := probe(rate)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			failed = rate
+			break
+		}
+		passed = rate
+		if rate == search.MaxRate {
+			break
+		}
+	}
+
+	// Binary phase: narrow the gap between the passing and failing rates
+	if failed > 0 {
+		low, high := passed, failed
+		for high-low > search.Precision {
+			rate := low + (high-low)/2
+			ok, err := probe(rate)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				low = rate
+			} else {
+				high = rate
+			}
+		}
+		passed = low
+	}
+
+	result.Capacity = passed
+	return result, nil
+}
+
+// probeRate runs a single attack at rate against node and checks it against
+// the SLO. The SLO applies to the deep metrics: HTTP-level success counts
+// JSON-RPC errors as successes, so a node failing every call fast would
+// otherwise sustain any rate.
+func probeRate(
+	node tooltypes.Node,
+	param tooltypes.TestGenerationParameters,
+	search tooltypes.CapacitySearchParameters,
+	rate int,
+	verbose bool,
+	includeDeepOutput []tooltypes.DeepOutput,
+) (*tooltypes.CapacityProbe, error) {
+	param.Rates = []int{rate}
+	param.Durations = []int{search.ProbeDuration}
+	param.Mode = nil
+	if !slices.Contains(includeDeepOutput, tooltypes.MetricsDeepOutput) {
+		includeDeepOutput = append(slices.Clone(includeDeepOutput), tooltypes.MetricsDeepOutput)
+	}
+
+	attacks, err := rpc_builder.GenerateTestWorkload(param)
+	if err != nil {
+		return nil, fmt.Errorf("error generating probe at %d rps: %w", rate, err)
+	}
+
+	outputs, err := RunRpcBenchmarks(
+		tooltypes.Nodes{node.Name: node},
+		tooltypes.LoadTest{TestParameters: param, Attacks: attacks},
+		verbose,
+		includeDeepOutput,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Success and throughput count the calls with a valid JSON-RPC result,
+	// and p99 is the latency of those calls
+	output := outputs[node.Name]
+	all := output.DeepMetrics[tooltypes.AllResponses]
+	successful := output.DeepMetrics[tooltypes.SuccessfulResponses]
+	p := &tooltypes.CapacityProbe{
+		Rate:       rate,
+		Success:    firstValue(all.Success),
+		P99:        firstValue(successful.P99),
+		Throughput: firstValue(all.Throughput),
+	}
+	p.Passed = p.Success != nil && *p.Success >= search.SLO.MinSuccess &&
+		p.P99 != nil && *p.P99 <= search.SLO.MaxP99
+	return p, nil
+}
+
+func firstValue(values []*float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.4f", *value)
+}
@@ -0,0 +1,131 @@
+package benchmarker
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+	"github.com/unifralabs/unifra-benchmark-tool/db"
+	"github.com/unifralabs/unifra-benchmark-tool/stats"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// OpenHistory opens the history database for the runs to be recorded in. The
+// history is best-effort: without it, runs are only not recorded.
+func OpenHistory() (tooltypes.RunHistory, func()) {
+	dbClient, err := db.NewClient()
+	if err != nil {
+		log.Warn().Msgf("Runs will not be recorded in the history: %v", err)
+		return nil, func() {}
+	}
+	return dbClient, func() { dbClient.Close() }
+}
+
+// saveRun records a run in the history. A run that cannot be recorded is
+// only logged, its results being saved to the output directory anyway.
+func saveRun(history tooltypes.RunHistory, run tooltypes.RunRecord, parameters interface{}) {
+	if history == nil {
+		return
+	}
+
+	encoded, err := json.Marshal(parameters)
+	if err != nil {
+		log.Warn().Msgf("Error encoding %s run parameters: %v", run.Kind, err)
+		return
+	}
+	run.Parameters = encoded
+	run.CLIArgs = os.Args
+
+	id, err := history.SaveRun(run)
+	if err != nil {
+		log.Warn().Msgf("Error recording %s run in the history: %v", run.Kind, err)
+		return
+	}
+	log.Info().Msgf("Run recorded in the history as run %d", id)
+}
+
+// loadTestAttacks flattens the outputs of the nodes into the metrics of their attacks
+func loadTestAttacks(outputs map[string]tooltypes.LoadTestOutput) []tooltypes.AttackMetrics {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attacks := []tooltypes.AttackMetrics{}
+	for _, name := range names {
+		output := outputs[name]
+		for i := range output.TargetRate {
+			attacks = append(attacks, tooltypes.AttackMetrics{
+				Node:           name,
+				Attack:         i,
+				TargetRate:     output.TargetRate[i],
+				TargetDuration: output.TargetDuration[i],
+				Requests:       output.Requests[i],
+				ActualRate:     output.ActualRate[i],
+				Throughput:     output.Throughput[i],
+				Success:        output.Success[i],
+				Min:            output.Min[i],
+				Mean:           output.Mean[i],
+				P50:            output.P50[i],
+				P90:            output.P90[i],
+				P95:            output.P95[i],
+				P99:            output.P99[i],
+				Max:            output.Max[i],
+			})
+		}
+	}
+	return attacks
+}
+
+// capacityAttacks flattens the probes of the capacity searches into the metrics of their attacks
+func capacityAttacks(results map[string]tooltypes.CapacityResult, probeDuration int) []tooltypes.AttackMetrics {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attacks := []tooltypes.AttackMetrics{}
+	for _, name := range names {
+		for i, probe := range results[name].Probes {
+			attacks = append(attacks, tooltypes.AttackMetrics{
+				Node:           name,
+				Attack:         i,
+				TargetRate:     probe.Rate,
+				TargetDuration: probeDuration,
+				Throughput:     probe.Throughput,
+				Success:        probe.Success,
+				P99:            probe.P99,
+			})
+		}
+	}
+	return attacks
+}
+
+// txRunResults converts the stats of a transaction benchmark
+func txRunResults(txType tooltypes.TxType, data *stats.CollectorData) *tooltypes.TxRunResults {
+	results := &tooltypes.TxRunResults{
+		TxType:   txType,
+		TPS:      data.TPS,
+		MinedTxs: data.MinedTxs,
+		Blocks:   make([]tooltypes.TxBlock, 0, len(data.BlockInfo)),
+	}
+	for _, info := range data.BlockInfo {
+		results.Blocks = append(results.Blocks, tooltypes.TxBlock{
+			BlockNum:       info.BlockNum,
+			CreatedAt:      info.CreatedAt,
+			NumTxs:         info.NumTxs,
+			GasUsed:        info.GasUsed,
+			GasLimit:       info.GasLimit,
+			GasUtilization: info.GasUtilization,
+			BlockTime:      info.BlockTime,
+			NumBlobs:       info.NumBlobs,
+			BlobGasUsed:    info.BlobGasUsed,
+			ExcessBlobGas:  info.ExcessBlobGas,
+		})
+	}
+	sort.Slice(results.Blocks, func(i, j int) bool { return results.Blocks[i].BlockNum < results.Blocks[j].BlockNum })
+	return results
+}
@@ -0,0 +1,154 @@
+package benchmarker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+	"github.com/unifralabs/unifra-benchmark-tool/vegeta"
+)
+
+// RunMultiNodeBenchmarks runs a load test against every node, repeats times,
+// in the given mode. When repeated, the results of each node are the medians
+// of its repeats, which are returned as well.
+func RunMultiNodeBenchmarks(
+	nodes tooltypes.Nodes,
+	test tooltypes.LoadTest,
+	verbose bool,
+	includeDeepOutput []tooltypes.DeepOutput,
+	mode tooltypes.NodesMode,
+	repeats int,
+) (map[string]tooltypes.LoadTestOutput, map[string][]tooltypes.LoadTestOutput, error) {
+	if repeats < 1 {
+		repeats = 1
+	}
+	if mode == "" {
+		mode = tooltypes.SequentialNodes
+	}
+
+	names := sortedNodeNames(nodes)
+	rng, err := utils.GetRNG(&test.TestParameters.RandomSeed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repeatResults := make(map[string][]tooltypes.LoadTestOutput)
+	for repeat := 0; repeat < repeats; repeat++ {
+		if verbose && repeats > 1 {
+			utils.PrintTimestamped(fmt.Sprintf("Repeat %d of %d", repeat+1, repeats))
+		}
+
+		var results map[string]tooltypes.LoadTestOutput
+		switch mode {
+		case tooltypes.SequentialNodes:
+			// Shuffle the order of the nodes so that none of them is always
+			// tested at the same time of the run
+			order := append([]string{}, names...)
+			rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+			results = make(map[string]tooltypes.LoadTestOutput)
+			for _, name := range order {
+				result, err := runLoadTestLocally(nodes[name], test, verbose, includeDeepOutput)
+				if err != nil {
+					return nil, nil, err
+				}
+				results[name] = result
+			}
+		case tooltypes.SimultaneousNodes:
+			results, err = runLoadTestSimultaneously(nodes, test, verbose, includeDeepOutput)
+			if err != nil {
+				return nil, nil, err
+			}
+		default:
+			return nil, nil, fmt.Errorf("unknown nodes mode: %s", mode)
+		}
+
+		for name, result := range results {
+			repeatResults[name] = append(repeatResults[name], result)
+		}
+	}
+
+	if repeats == 1 {
+		results := make(map[string]tooltypes.LoadTestOutput)
+		for name, outputs := range repeatResults {
+			results[name] = outputs[0]
+		}
+		return results, nil, nil
+	}
+
+	results := make(map[string]tooltypes.LoadTestOutput)
+	for name, outputs := range repeatResults {
+		results[name] = tooltypes.MedianLoadTestOutput(outputs)
+	}
+	return results, repeatResults, nil
+}
+
+// runLoadTestSimultaneously runs each attack against all nodes at once, so
+// that every node receives the same calls under the same conditions
+func runLoadTestSimultaneously(
+	nodes tooltypes.Nodes,
+	test tooltypes.LoadTest,
+	verbose bool,
+	includeDeepOutput []tooltypes.DeepOutput,
+) (map[string]tooltypes.LoadTestOutput, error) {
+	names := sortedNodeNames(nodes)
+	if verbose {
+		utils.PrintTimestamped(fmt.Sprintf("Running load test for %d nodes simultaneously", len(names)))
+	}
+
+	nodeResults := make(map[string][]*tooltypes.LoadTestOutputDatum)
+	for _, attack := range test.Attacks {
+		if verbose {
+			utils.PrintTimestamped(fmt.Sprintf("Running attack at rate = %d rps", attack.Rate))
+		}
+
+		results := make([]*tooltypes.LoadTestOutputDatum, len(names))
+		errs := make([]error, len(names))
+		var wg sync.WaitGroup
+		for i, name := range names {
+			wg.Add(1)
+			go func(i int, node tooltypes.Node) {
+				defer wg.Done()
+				results[i], errs[i] = vegeta.RunVegetaAttack(
+					node,
+					attack.Rate,
+					attack.Calls,
+					attack.Duration,
+					attack.VegetaArgs,
+					false,
+					includeDeepOutput,
+				)
+			}(i, nodes[name])
+		}
+		wg.Wait()
+
+		for i, name := range names {
+			if errs[i] != nil {
+				return nil, fmt.Errorf("%s: %w", name, errs[i])
+			}
+			nodeResults[name] = append(nodeResults[name], results[i])
+
+			if verbose {
+				utils.PrintBullet(name)
+				printAttackDetails(results[i])
+			}
+		}
+	}
+
+	outputs := make(map[string]tooltypes.LoadTestOutput)
+	for name, results := range nodeResults {
+		outputs[name] = tooltypes.BuildLoadTestOutput(results)
+	}
+	return outputs, nil
+}
+
+func sortedNodeNames(nodes tooltypes.Nodes) []string {
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
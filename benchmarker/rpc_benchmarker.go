@@ -1,40 +1,153 @@
 package benchmarker
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"time"
 
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog/log"
 	"github.com/unifralabs/unifra-benchmark-tool/config"
 	"github.com/unifralabs/unifra-benchmark-tool/outputter"
 	"github.com/unifralabs/unifra-benchmark-tool/rpc_builder"
+	"github.com/unifralabs/unifra-benchmark-tool/rpc_client"
+	"github.com/unifralabs/unifra-benchmark-tool/samples"
 	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
 	"github.com/unifralabs/unifra-benchmark-tool/utils"
 	"github.com/unifralabs/unifra-benchmark-tool/vegeta"
 )
 
 type RpcBenchmarker struct {
-	cfg   *config.EnvConfig
-	nodes tooltypes.Nodes
+	cfg     *config.EnvConfig
+	nodes   tooltypes.Nodes
+	history tooltypes.RunHistory
 }
 
-func NewRpcBenchmarker(cfg *config.EnvConfig, nodes tooltypes.Nodes) (*RpcBenchmarker, error) {
+// NewRpcBenchmarker creates the RPC benchmarker of the nodes. Its runs are
+// recorded in history, unless nil.
+func NewRpcBenchmarker(cfg *config.EnvConfig, nodes tooltypes.Nodes, history tooltypes.RunHistory) (*RpcBenchmarker, error) {
+	if cfg.SamplesDir != "" {
+		samples.Dir = cfg.SamplesDir
+	}
+
 	return &RpcBenchmarker{
-		cfg:   cfg,
-		nodes: nodes,
+		cfg:     cfg,
+		nodes:   nodes,
+		history: history,
 	}, nil
 }
 
 func (b *RpcBenchmarker) Run() error {
+	scenario, err := b.scenarioFromConfig()
+	if err != nil {
+		return err
+	}
+	return b.RunScenario(scenario)
+}
+
+// scenarioFromConfig describes the RPC test configured by the environment
+func (b *RpcBenchmarker) scenarioFromConfig() (tooltypes.RpcScenario, error) {
+	scenario := tooltypes.RpcScenario{
+		Network:       b.cfg.Network,
+		Workload:      rpc_builder.DefaultWorkload,
+		BlockRange:    b.cfg.BlockRange,
+		SamplesDir:    b.cfg.SamplesDir,
+		HarvestBlocks: b.cfg.SamplesHarvestBlocks,
+		NodesMode:     tooltypes.NodesMode(b.cfg.NodesMode),
+		Repeats:       b.cfg.NodesRepeats,
+		SaveTest:      b.cfg.SaveLoadTest,
+		Replay:        b.cfg.ReplayLoadTest,
+	}
+
+	if b.cfg.VerifyResponses {
+		scenario.Verify = &tooltypes.VerificationParameters{Reference: b.cfg.VerifyReference}
+	}
+
+	if b.cfg.RpcWorkload != "" {
+		workload, err := rpc_builder.ParseWorkload(b.cfg.RpcWorkload)
+		if err != nil {
+			return tooltypes.RpcScenario{}, fmt.Errorf("error parsing workload: %w", err)
+		}
+		scenario.Workload = workload
+	}
+
+	if b.cfg.LoadTestMode != "" {
+		mode := b.loadTestModeParameters()
+		scenario.Mode = &mode
+	}
+
+	if b.cfg.CapacitySearch {
+		search := b.capacitySearchParameters()
+		scenario.CapacitySearch = &search
+	}
+
+	return scenario, nil
+}
+
+// RunScenario runs the RPC load test, or the capacity search, of a scenario
+// against the nodes and saves its results
+func (b *RpcBenchmarker) RunScenario(scenario tooltypes.RpcScenario) error {
+	if scenario.Replay != "" {
+		return b.replayLoadTest(scenario)
+	}
+
+	if scenario.SamplesDir != "" {
+		samples.Dir = scenario.SamplesDir
+	}
+
+	workload := scenario.Workload
+	if len(workload) == 0 {
+		workload = rpc_builder.DefaultWorkload
+	}
+
+	if err := b.prepareSamples(scenario.Network, scenario.HarvestBlocks); err != nil {
+		return fmt.Errorf("error preparing samples: %w", err)
+	}
+
+	blockRange, err := b.resolveBlockRange(scenario.BlockRange, scenario.Network)
+	if err != nil {
+		return fmt.Errorf("error resolving block range: %w", err)
+	}
+
+	randomSeed := tooltypes.RandomSeed(time.Now().UnixNano())
+	if scenario.RandomSeed != nil {
+		randomSeed = *scenario.RandomSeed
+	}
 
 	param := tooltypes.TestGenerationParameters{
 		TestName:   b.cfg.TestName,
-		RandomSeed: tooltypes.RandomSeed(time.Now().UnixNano()),
-		Rates:      []int{100},
-		Durations:  []int{5},
+		RandomSeed: randomSeed,
+		Rates:      scenario.Rates,
+		Durations:  scenario.Durations,
 		VegetaArgs: nil,
+		Network:    scenario.Network,
+		Workload:   workload,
+		BlockRange: blockRange,
+		Mode:       scenario.Mode,
+	}
+	if scenario.VegetaArgs != nil {
+		param.VegetaArgs = *scenario.VegetaArgs
+	}
+	if param.Mode != nil {
+		param.Rates, param.Durations, err = tooltypes.GenerateLoadTestSchedule(*param.Mode)
+		if err != nil {
+			return fmt.Errorf("error generating %s schedule: %w", param.Mode.Mode, err)
+		}
+	}
+	if len(param.Rates) == 0 {
+		param.Rates = []int{100}
+		param.Durations = []int{5}
 	}
-	attacks, err := rpc_builder.GenerateTestEthGetBalance(param)
+
+	includeDeepOutput := deepOutputsForWorkload(workload, scenario.DeepOutput)
+
+	if scenario.CapacitySearch != nil {
+		return b.runCapacitySearch(param, ApplyCapacitySearchDefaults(*scenario.CapacitySearch), includeDeepOutput)
+	}
+
+	attacks, err := rpc_builder.GenerateTestWorkload(param)
 	if err != nil {
 		return fmt.Errorf("error generating test: %w", err)
 	}
@@ -43,7 +156,58 @@ func (b *RpcBenchmarker) Run() error {
 		TestParameters: param,
 		Attacks:        attacks,
 	}
-	output, err := RunRpcBenchmarks(b.nodes, loadTest, true, []tooltypes.DeepOutput{})
+
+	if scenario.SaveTest != "" {
+		if err := outputter.SaveLoadTest(scenario.SaveTest, loadTest); err != nil {
+			return fmt.Errorf("error saving load test: %w", err)
+		}
+	}
+
+	return b.runLoadTest(loadTest, includeDeepOutput, scenario.NodesMode, scenario.Repeats, scenario.Verify)
+}
+
+// replayLoadTest runs a load test saved by a previous run, sending the exact
+// same requests
+func (b *RpcBenchmarker) replayLoadTest(scenario tooltypes.RpcScenario) error {
+	path := scenario.Replay
+	loadTest, err := outputter.LoadLoadTest(path)
+	if err != nil {
+		return fmt.Errorf("error loading load test: %w", err)
+	}
+	log.Info().Msgf("Replaying %s (seed %d, %d attacks)", path, loadTest.TestParameters.RandomSeed, len(loadTest.Attacks))
+
+	if b.cfg.TestName != "" {
+		loadTest.TestParameters.TestName = b.cfg.TestName
+	}
+
+	includeDeepOutput := deepOutputsForWorkload(loadTest.TestParameters.Workload, scenario.DeepOutput)
+	return b.runLoadTest(loadTest, includeDeepOutput, scenario.NodesMode, scenario.Repeats, scenario.Verify)
+}
+
+// deepOutputsForWorkload adds the deep metrics that mixed workloads need for
+// their per-method breakdown
+func deepOutputsForWorkload(workload tooltypes.Workload, deepOutput []tooltypes.DeepOutput) []tooltypes.DeepOutput {
+	if len(workload) > 1 && !slices.Contains(deepOutput, tooltypes.MetricsDeepOutput) {
+		deepOutput = append(slices.Clone(deepOutput), tooltypes.MetricsDeepOutput)
+	}
+	return deepOutput
+}
+
+func (b *RpcBenchmarker) runLoadTest(
+	loadTest tooltypes.LoadTest,
+	includeDeepOutput []tooltypes.DeepOutput,
+	mode tooltypes.NodesMode,
+	repeats int,
+	verify *tooltypes.VerificationParameters,
+) error {
+	// Verification compares the response bodies kept by the raw output
+	keepRawOutput := slices.Contains(includeDeepOutput, tooltypes.RawDeepOutput)
+	if verify != nil && !keepRawOutput {
+		includeDeepOutput = append(slices.Clone(includeDeepOutput), tooltypes.RawDeepOutput)
+	}
+
+	tStart := time.Now()
+	output, repeatOutputs, err := RunMultiNodeBenchmarks(b.nodes, loadTest, true, includeDeepOutput, mode, repeats)
 
 	if err != nil {
 		log.Info().Msgf("Error running vegeta attack: %s", err)
@@ -52,12 +216,237 @@ func (b *RpcBenchmarker) Run() error {
 
 	// log.Info().Msgf("output: %s", output)
 
+	if verify != nil {
+		if err := b.verifyResponses(loadTest, output, repeatOutputs, *verify); err != nil {
+			return err
+		}
+		if !keepRawOutput {
+			output = withoutRawOutput(output)
+			for _, outputs := range repeatOutputs {
+				for i := range outputs {
+					outputs[i].DeepRawOutput = nil
+				}
+			}
+		}
+	}
+
+	tEnd := time.Now()
+	_, err = outputter.SaveSingleRunResults(b.cfg.OutputDir, b.nodes, output, repeatOutputs, true, loadTest.TestParameters.TestName, tStart.Unix(), tEnd.Unix())
+	if err != nil {
+		return err
+	}
+
+	saveRun(b.history, tooltypes.RunRecord{
+		Kind:      tooltypes.RpcRun,
+		TestName:  loadTest.TestParameters.TestName,
+		TRunStart: tStart.Unix(),
+		TRunEnd:   tEnd.Unix(),
+		Nodes:     b.nodes,
+		Attacks:   loadTestAttacks(output),
+	}, loadTest.TestParameters)
+	return nil
+}
+
+// verifyResponses compares the responses of the nodes, of every repeat, and
+// saves the mismatches
+func (b *RpcBenchmarker) verifyResponses(
+	loadTest tooltypes.LoadTest,
+	output map[string]tooltypes.LoadTestOutput,
+	repeatOutputs map[string][]tooltypes.LoadTestOutput,
+	verify tooltypes.VerificationParameters,
+) error {
+	if len(b.nodes) < 2 && verify.Reference == "" {
+		log.Warn().Msg("Response verification needs several nodes or a reference node, skipping it")
+		return nil
+	}
+
+	runs := []map[string]tooltypes.LoadTestOutput{output}
+	if repeatOutputs != nil {
+		runs = nil
+		for name, outputs := range repeatOutputs {
+			for i, repeatOutput := range outputs {
+				if i == len(runs) {
+					runs = append(runs, make(map[string]tooltypes.LoadTestOutput))
+				}
+				runs[i][name] = repeatOutput
+			}
+		}
+	}
+
+	report, err := VerifyResponses(b.nodes, loadTest, runs, verify)
+	if err != nil {
+		return fmt.Errorf("error verifying responses: %w", err)
+	}
+	outputter.PrintVerificationReport(report)
+	return outputter.SaveVerificationReport(b.cfg.OutputDir, report)
+}
+
+func withoutRawOutput(outputs map[string]tooltypes.LoadTestOutput) map[string]tooltypes.LoadTestOutput {
+	stripped := make(map[string]tooltypes.LoadTestOutput, len(outputs))
+	for name, output := range outputs {
+		output.DeepRawOutput = nil
+		stripped[name] = output
+	}
+	return stripped
+}
+
+func (b *RpcBenchmarker) runCapacitySearch(param tooltypes.TestGenerationParameters, search tooltypes.CapacitySearchParameters, includeDeepOutput []tooltypes.DeepOutput) error {
 	tStart := time.Now()
-	outputter.SaveSingleRunResults(b.cfg.OutputDir, b.nodes, output, true, loadTest.TestParameters.TestName, tStart.Unix(), time.Now().Unix())
+	results, err := SearchMaxThroughput(b.nodes, param, search, true, includeDeepOutput)
+	if err != nil {
+		return fmt.Errorf("error searching max throughput: %w", err)
+	}
+	tEnd := time.Now()
+
+	outputter.PrintCapacityResults(results)
+	_, err = outputter.SaveCapacityResults(b.cfg.OutputDir, b.nodes, search, results, tStart.Unix(), tEnd.Unix())
+	if err != nil {
+		return err
+	}
 
+	saveRun(b.history, tooltypes.RunRecord{
+		Kind:      tooltypes.CapacityRun,
+		TestName:  param.TestName,
+		TRunStart: tStart.Unix(),
+		TRunEnd:   tEnd.Unix(),
+		Nodes:     b.nodes,
+		Attacks:   capacityAttacks(results, search.ProbeDuration),
+	}, search)
 	return nil
 }
 
+func (b *RpcBenchmarker) capacitySearchParameters() tooltypes.CapacitySearchParameters {
+	return tooltypes.CapacitySearchParameters{
+		StartRate:     b.cfg.CapacityStartRate,
+		MaxRate:       b.cfg.CapacityMaxRate,
+		Precision:     b.cfg.CapacityPrecision,
+		ProbeDuration: b.cfg.CapacityProbeDuration,
+		SLO: tooltypes.SLO{
+			MinSuccess: b.cfg.SloMinSuccess,
+			MaxP99:     b.cfg.SloMaxP99.Seconds(),
+		},
+	}
+}
+
+// ApplyCapacitySearchDefaults fills the unset capacity search parameters
+func ApplyCapacitySearchDefaults(search tooltypes.CapacitySearchParameters) tooltypes.CapacitySearchParameters {
+	if search.StartRate == 0 {
+		search.StartRate = 10
+	}
+	if search.MaxRate == 0 {
+		search.MaxRate = 10000
+	}
+	if search.Precision == 0 {
+		search.Precision = 10
+	}
+	if search.ProbeDuration == 0 {
+		search.ProbeDuration = 10
+	}
+	if search.SLO.MinSuccess == 0 {
+		search.SLO.MinSuccess = 0.999
+	}
+	if search.SLO.MaxP99 == 0 {
+		search.SLO.MaxP99 = 0.5
+	}
+	return search
+}
+
+func (b *RpcBenchmarker) loadTestModeParameters() tooltypes.LoadTestModeParameters {
+	return tooltypes.LoadTestModeParameters{
+		Mode:               tooltypes.LoadTestMode(b.cfg.LoadTestMode),
+		BaseRate:           b.cfg.LoadTestBaseRate,
+		PeakRate:           b.cfg.LoadTestPeakRate,
+		Step:               b.cfg.LoadTestStep,
+		StepDuration:       b.cfg.LoadTestStepDuration,
+		BurstDuration:      b.cfg.LoadTestBurstDuration,
+		Duration:           b.cfg.LoadTestDuration,
+		CheckpointInterval: b.cfg.LoadTestCheckpointInterval,
+	}
+}
+
+// resolveBlockRange asks every node for its head and earliest available
+// state, and registers the block range of spec that all of them can serve
+func (b *RpcBenchmarker) resolveBlockRange(blockRangeSpec string, network string) (*tooltypes.BlockRange, error) {
+	if blockRangeSpec == "" {
+		return nil, nil
+	}
+
+	spec, err := rpc_builder.ParseBlockRangeSpec(blockRangeSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := []tooltypes.BlockRange{}
+	for _, node := range b.nodes {
+		client, err := rpc_client.NewRpcClient(node.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", node.Name, err)
+		}
+
+		info, err := client.GetChainInfo()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chain info of %s: %w", node.Name, err)
+		}
+		log.Info().Msgf("%s: head %d, earliest state %d, archive %t", node.Name, info.Head, info.EarliestState, info.Archive)
+
+		r, err := rpc_builder.ResolveBlockRange(spec, *info)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", node.Name, err)
+		}
+		ranges = append(ranges, r)
+	}
+
+	r, err := rpc_builder.IntersectBlockRanges(ranges)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Using blocks %d-%d", r.Start, r.End)
+
+	rpc_builder.SetNetworkBlockRange(network, r)
+	return &r, nil
+}
+
+// prepareSamples harvests the sample datasets of the network from the recent
+// blocks of a node, when they are missing and harvesting is enabled
+func (b *RpcBenchmarker) prepareSamples(network string, harvestBlocks int) error {
+	if harvestBlocks <= 0 || len(b.nodes) == 0 {
+		return nil
+	}
+
+	missing := false
+	for _, datatype := range []samples.Datatype{samples.Transactions, samples.Contracts, samples.EOAs, samples.Blocks} {
+		if !samples.Exists(network, datatype) {
+			missing = true
+		}
+	}
+	if !missing {
+		return nil
+	}
+
+	node := b.nodes[sortedNodeNames(b.nodes)[0]]
+
+	client, err := ethclient.Dial(node.URL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	head, err := client.BlockNumber(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get head block of %s: %w", node.Name, err)
+	}
+
+	startBlock := uint64(0)
+	if head >= uint64(harvestBlocks) {
+		startBlock = head - uint64(harvestBlocks) + 1
+	}
+
+	if network == "" {
+		network = samples.DefaultNetwork
+	}
+	return samples.Harvest(client, network, startBlock, head)
+}
+
 func RunRpcBenchmarks(
 	parsedNodes tooltypes.Nodes,
 	test tooltypes.LoadTest,
@@ -96,7 +485,7 @@ func runLoadTestLocally(
 		}
 
 		result, err := vegeta.RunVegetaAttack(
-			node.URL,
+			node,
 			attack.Rate,
 			attack.Calls,
 			attack.Duration,
@@ -111,6 +500,7 @@ func runLoadTestLocally(
 		results = append(results, result)
 
 		if verbose {
+			printAttackDetails(result)
 		}
 	}
 
@@ -144,3 +534,52 @@ func runLoadTestLocally(
 
 	return outputData, nil
 }
+
+// printAttackDetails prints the per-method metrics and the error groups of an attack
+func printAttackDetails(result *tooltypes.LoadTestOutputDatum) {
+	if len(result.DeepMethodMetrics) > 1 {
+		printMethodMetrics(result.DeepMethodMetrics)
+	}
+
+	if len(result.DeepRPCErrorGroups) > 0 {
+		rows := make([][]string, 0, len(result.DeepRPCErrorGroups))
+		for _, group := range result.DeepRPCErrorGroups {
+			code := "-"
+			if group.Code != nil {
+				code = fmt.Sprintf("%d", *group.Code)
+			}
+			rows = append(rows, []string{group.Method, code, group.Message, fmt.Sprintf("%d", group.Count)})
+		}
+		utils.PrintTable(rows, []string{"method", "code", "message", "count"})
+	}
+}
+
+func printMethodMetrics(methodMetrics map[string]tooltypes.LoadTestDeepOutputDatum) {
+	methods := make([]string, 0, len(methodMetrics))
+	for method := range methodMetrics {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	formatFloat := func(value *float64, format string) string {
+		if value == nil {
+			return "-"
+		}
+		return fmt.Sprintf(format, *value)
+	}
+
+	rows := make([][]string, 0, len(methods))
+	for _, method := range methods {
+		metrics := methodMetrics[method]
+		rows = append(rows, []string{
+			method,
+			fmt.Sprintf("%d", metrics.Requests),
+			formatFloat(metrics.Success, "%.4f"),
+			formatFloat(metrics.Mean, "%.6f"),
+			formatFloat(metrics.P50, "%.6f"),
+			formatFloat(metrics.P99, "%.6f"),
+			fmt.Sprintf("%d", metrics.NRPCErrors),
+		})
+	}
+	utils.PrintTable(rows, []string{"method", "requests", "success", "mean (s)", "p50 (s)", "p99 (s)", "rpc errors"})
+}
@@ -2,10 +2,14 @@ package benchmarker
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/unifralabs/unifra-benchmark-tool/contract/stress"
 	"github.com/unifralabs/unifra-benchmark-tool/distributor"
+	"github.com/unifralabs/unifra-benchmark-tool/metrics"
 	"github.com/unifralabs/unifra-benchmark-tool/outputter"
 	"github.com/unifralabs/unifra-benchmark-tool/rpc_client"
 	"github.com/unifralabs/unifra-benchmark-tool/stats"
@@ -16,7 +20,9 @@ import (
 
 type TxBenchmarker struct {
 	txType           tooltypes.TxType
+	options          tooltypes.TxOptions
 	mnemonic         string
+	node             tooltypes.Node
 	url              string
 	provider         *ethclient.Client
 	rpcClient        *rpc_client.RpcClient
@@ -26,9 +32,12 @@ type TxBenchmarker struct {
 	batchSize        int
 	outputDir        string
 	accountIndexes   []int
+	fees             *tooltypes.TxFees // fees the sub-accounts are funded for
 }
 
-func NewTxBenchmarker(client *ethclient.Client, mnemonic, url string, txType tooltypes.TxType,
+// NewTxBenchmarker creates the benchmark of a transaction type, sending the
+// transactions to node
+func NewTxBenchmarker(client *ethclient.Client, mnemonic string, node tooltypes.Node, txType tooltypes.TxType, options tooltypes.TxOptions,
 	subAccountsCount int, transactionCount int, batchSize int, outputDir string) (*TxBenchmarker, error) {
 
 	rpcClient, err := rpc_client.NewRpcClientFromEthClient(client)
@@ -36,21 +45,36 @@ func NewTxBenchmarker(client *ethclient.Client, mnemonic, url string, txType too
 		return nil, fmt.Errorf("error creating eth client: %s", err)
 	}
 
+	url := node.URL
 	var txBuilder tooltypes.TxBuilder
 	switch txType {
 	case tooltypes.EOA:
-		txBuilder, err = tx_builder.NewEOATxBuilder(mnemonic, url)
+		txBuilder, err = tx_builder.NewEOATxBuilder(mnemonic, url, options.FeeMode)
 	case tooltypes.ERC20:
-		txBuilder, err = tx_builder.NewERC20TxBuilder(mnemonic, url)
+		txBuilder, err = tx_builder.NewERC20TxBuilder(mnemonic, url, options.FeeMode, options.Contracts, transactionCount)
 	case tooltypes.ERC721:
-		txBuilder, err = tx_builder.NewERC721TxBuilder(mnemonic, url)
+		txBuilder, err = tx_builder.NewERC721TxBuilder(mnemonic, url, options.FeeMode, options.Contracts)
+	case tooltypes.BLOB:
+		txBuilder, err = tx_builder.NewBlobTxBuilder(mnemonic, url, options.BlobsPerTx)
+	case tooltypes.SETCODE:
+		txBuilder, err = tx_builder.NewSetCodeTxBuilder(mnemonic, url, options.Contracts)
+	case tooltypes.CONTRACT:
+		txBuilder, err = tx_builder.NewContractTxBuilder(mnemonic, url, options.FeeMode, options.Contract)
+	case tooltypes.SSTORE, tooltypes.SLOAD, tooltypes.KECCAK, tooltypes.CALLDATA, tooltypes.LOGS, tooltypes.CREATE:
+		kind := stress.Kind(strings.ToLower(string(txType)))
+		txBuilder, err = tx_builder.NewStressTxBuilder(mnemonic, url, options.FeeMode, kind, options.GasPerTx, options.Contracts)
 	default:
 		return nil, fmt.Errorf("unknown runtime mode: %s", txType)
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	return &TxBenchmarker{
 		txType:           txType,
+		options:          options,
 		mnemonic:         mnemonic,
+		node:             node,
 		url:              url,
 		provider:         client,
 		rpcClient:        rpcClient,
@@ -69,8 +93,15 @@ func (t *TxBenchmarker) Initialize() error {
 		return err
 	}
 
+	// The transactions are built with the fees the distribution budgets for,
+	// so that a rising base fee cannot underfund the sub-accounts
+	t.fees, err = t.txBuilder.GetFees()
+	if err != nil {
+		return fmt.Errorf("failed to get fees: %v", err)
+	}
+
 	// Distribute the native currency funds
-	d, err := distributor.NewDistributor(t.mnemonic, t.subAccountsCount, t.transactionCount, t.txBuilder, t.url)
+	d, err := distributor.NewDistributor(t.mnemonic, t.subAccountsCount, t.transactionCount, t.txBuilder, t.fees, t.url)
 	if err != nil {
 		return err
 	}
@@ -95,26 +126,63 @@ func (t *TxBenchmarker) Initialize() error {
 	return nil
 }
 
-func (t *TxBenchmarker) Run() error {
-	ctx := NewTxBenchmarkerContext(t.accountIndexes, t.transactionCount, t.batchSize, t.mnemonic, t.url)
+// Run sends the transactions and returns their stats, which are also saved
+// to the results file of the transaction type if there is an output directory
+func (t *TxBenchmarker) Run() (*stats.CollectorData, error) {
+	tStart := time.Now()
+	progress, err := metrics.NewTxProgress(t.provider, strings.ToLower(string(t.txType)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the tx metrics: %v", err)
+	}
+	defer progress.Stop()
+
+	ctx := NewTxBenchmarkerContext(t.accountIndexes, t.transactionCount, t.batchSize, t.mnemonic, t.url, t.fees)
+	ctx.Progress = progress
 	txHashes, err := BuildAndSendTransactions(t.provider, t.txBuilder, ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Collect the data
 	collectorData, err := stats.GenerateStats(t.provider, txHashes, t.batchSize)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	progress.Stop()
+
+	tEnd := time.Now()
 
 	// Output the data if needed
 	if t.outputDir != "" {
-		err := outputter.OutputData(collectorData, t.outputDir)
-		return err
+		if err := outputter.OutputData(collectorData, t.outputDir, t.txType); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	saveRun(t.options.History, tooltypes.RunRecord{
+		Kind:      tooltypes.TxRun,
+		TestName:  strings.ToLower(string(t.txType)),
+		TRunStart: tStart.Unix(),
+		TRunEnd:   tEnd.Unix(),
+		Nodes:     tooltypes.Nodes{t.node.Name: t.node},
+		Tx:        txRunResults(t.txType, collectorData),
+	}, t.scenario())
+
+	return collectorData, nil
+}
+
+// scenario describes the benchmark, as recorded in the history
+func (t *TxBenchmarker) scenario() tooltypes.TxScenario {
+	return tooltypes.TxScenario{
+		Type:         strings.ToLower(string(t.txType)),
+		Accounts:     t.subAccountsCount,
+		Transactions: t.transactionCount,
+		BatchSize:    t.batchSize,
+		FeeMode:      string(t.options.FeeMode),
+		BlobsPerTx:   t.options.BlobsPerTx,
+		GasPerTx:     t.options.GasPerTx,
+		Contract:     t.options.Contract,
+	}
 }
 
 type TxBenchmarkerContext struct {
@@ -123,15 +191,20 @@ type TxBenchmarkerContext struct {
 	BatchSize      int
 	Mnemonic       string
 	URL            string
+	Fees           *tooltypes.TxFees
+
+	// Progress follows the transactions in the metrics, nil when they are not served
+	Progress *metrics.TxProgress
 }
 
-func NewTxBenchmarkerContext(accountIndexes []int, numTxs, batchSize int, mnemonic, url string) *TxBenchmarkerContext {
+func NewTxBenchmarkerContext(accountIndexes []int, numTxs, batchSize int, mnemonic, url string, fees *tooltypes.TxFees) *TxBenchmarkerContext {
 	return &TxBenchmarkerContext{
 		AccountIndexes: accountIndexes,
 		NumTxs:         numTxs,
 		BatchSize:      batchSize,
 		Mnemonic:       mnemonic,
 		URL:            url,
+		Fees:           fees,
 	}
 }
 
@@ -144,7 +217,7 @@ func BuildAndSendTransactions(ethclient *ethclient.Client, runtime tooltypes.TxB
 	}
 
 	// Construct the transactions
-	rawTransactions, err := runtime.ConstructTransactions(accounts, ctx.NumTxs)
+	rawTransactions, err := runtime.ConstructTransactions(accounts, ctx.NumTxs, ctx.Fees)
 	if err != nil {
 		return nil, err
 	}
@@ -156,9 +229,10 @@ func BuildAndSendTransactions(ethclient *ethclient.Client, runtime tooltypes.TxB
 	}
 
 	batches := utils.GenerateBatches(signedTransactions, ctx.BatchSize)
+	ctx.Progress.Track(signedTransactions)
 
 	// Send the transactions in batches
-	_, err = utils.BatchSendRawTransactions(batches, ctx.URL)
+	_, err = utils.BatchSendRawTransactions(batches, ctx.URL, ctx.Progress.Sent)
 	if err != nil {
 		return nil, err
 	}
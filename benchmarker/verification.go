@@ -0,0 +1,250 @@
+package benchmarker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+	"github.com/unifralabs/unifra-benchmark-tool/vegeta"
+)
+
+const (
+	// DefaultVerificationSamples is the number of mismatches kept per method and node
+	DefaultVerificationSamples = 10
+	// referenceNodeRate is the rate at which the calls are sent to an extra reference node
+	referenceNodeRate = 50
+)
+
+// headBlockTags are the block tags whose results move with the chain head
+var headBlockTags = []string{"latest", "pending", "safe", "finalized"}
+
+// verifiedResponse is the response of a node to a call, with its normalized result
+type verifiedResponse struct {
+	body   []byte
+	result string
+}
+
+// VerifyResponses compares the normalized results returned by the nodes to
+// the identical calls of a load test. runs are the outputs of each repeat of
+// the load test, which must include the deep raw output.
+func VerifyResponses(
+	nodes tooltypes.Nodes,
+	test tooltypes.LoadTest,
+	runs []map[string]tooltypes.LoadTestOutput,
+	params tooltypes.VerificationParameters,
+) (*tooltypes.VerificationReport, error) {
+	if params.MaxSamples <= 0 {
+		params.MaxSamples = DefaultVerificationSamples
+	}
+
+	names := sortedNodeNames(nodes)
+	responses := make(map[string]map[*tooltypes.JsonrpcMessage]verifiedResponse)
+	calls := []*tooltypes.JsonrpcMessage{}
+	seen := make(map[*tooltypes.JsonrpcMessage]bool)
+	for _, name := range names {
+		responses[name] = make(map[*tooltypes.JsonrpcMessage]verifiedResponse)
+		for _, run := range runs {
+			output := run[name]
+			for i, attack := range test.Attacks {
+				if i >= len(output.DeepRawOutput) || output.DeepRawOutput[i] == nil {
+					return nil, fmt.Errorf("%s: no raw output to verify for attack %d", name, i)
+				}
+				records, err := vegeta.DecodeRawResponseRecords(*output.DeepRawOutput[i], attack.Calls)
+				if err != nil {
+					return nil, fmt.Errorf("%s: error decoding raw output: %w", name, err)
+				}
+				for _, record := range records {
+					if record.Call == nil || record.StatusCode != 200 {
+						continue
+					}
+					if _, exists := responses[name][record.Call]; exists {
+						continue
+					}
+					if response, ok := newVerifiedResponse(record.Response); ok {
+						responses[name][record.Call] = response
+						if !seen[record.Call] {
+							seen[record.Call] = true
+							calls = append(calls, record.Call)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	reference := names[0]
+	if params.Reference != "" {
+		if _, ok := nodes[params.Reference]; ok {
+			reference = params.Reference
+		} else {
+			node, err := utils.ParseNode(params.Reference, false)
+			if err != nil {
+				return nil, fmt.Errorf("invalid reference node: %w", err)
+			}
+			reference = node.Name
+			if responses[reference], err = fetchReferenceResponses(node, calls); err != nil {
+				return nil, fmt.Errorf("error querying reference node %s: %w", reference, err)
+			}
+		}
+	}
+
+	report := &tooltypes.VerificationReport{Reference: reference}
+	methods := make(map[string]*tooltypes.MethodVerification)
+	for _, name := range names {
+		if name == reference {
+			continue
+		}
+		report.Nodes = append(report.Nodes, name)
+
+		for _, call := range calls {
+			method, ok := methods[call.Method]
+			if !ok {
+				method = &tooltypes.MethodVerification{Method: call.Method, Mismatches: []tooltypes.ResponseMismatch{}}
+				methods[call.Method] = method
+			}
+
+			expected, hasExpected := responses[reference][call]
+			actual, hasActual := responses[name][call]
+			if !hasExpected || !hasActual || dependsOnHead(call) {
+				method.Skipped++
+				continue
+			}
+
+			method.Compared++
+			if actual.result == expected.result {
+				continue
+			}
+			method.Mismatched++
+			if countMismatches(method.Mismatches, name) < params.MaxSamples {
+				method.Mismatches = append(method.Mismatches, tooltypes.ResponseMismatch{
+					Node:              name,
+					Request:           call,
+					Response:          actual.body,
+					ReferenceResponse: expected.body,
+				})
+			}
+		}
+	}
+
+	for _, method := range methods {
+		report.Methods = append(report.Methods, *method)
+	}
+	sort.Slice(report.Methods, func(i, j int) bool { return report.Methods[i].Method < report.Methods[j].Method })
+
+	return report, nil
+}
+
+// fetchReferenceResponses sends each call once to a node that is not under test
+func fetchReferenceResponses(node tooltypes.Node, calls []*tooltypes.JsonrpcMessage) (map[*tooltypes.JsonrpcMessage]verifiedResponse, error) {
+	utils.PrintTimestamped(fmt.Sprintf("Sending %d calls to reference node %s", len(calls), node.Name))
+
+	responses := make(map[*tooltypes.JsonrpcMessage]verifiedResponse)
+	if len(calls) == 0 {
+		return responses, nil
+	}
+
+	results, err := vegeta.FetchResponses(node.URL, calls, referenceNodeRate)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		if result.Code != 200 {
+			continue
+		}
+		if response, ok := newVerifiedResponse(result.Body); ok {
+			responses[calls[i]] = response
+		}
+	}
+	return responses, nil
+}
+
+// newVerifiedResponse normalizes the result of a successful JSON-RPC response
+func newVerifiedResponse(body []byte) (verifiedResponse, bool) {
+	var response tooltypes.JsonrpcMessage
+	if err := json.Unmarshal(body, &response); err != nil || response.Error != nil {
+		return verifiedResponse{}, false
+	}
+
+	result, err := normalizeResult(response.Result)
+	if err != nil {
+		return verifiedResponse{}, false
+	}
+	return verifiedResponse{body: body, result: result}, true
+}
+
+// normalizeResult re-encodes a result with sorted object keys and lowercase
+// hex strings, so that only differences in the data itself are reported
+func normalizeResult(result json.RawMessage) (string, error) {
+	if len(result) == 0 {
+		return "null", nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(result))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return "", err
+	}
+
+	normalized, err := json.Marshal(lowercaseHex(value))
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
+
+func lowercaseHex(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+			return strings.ToLower(v)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = lowercaseHex(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = lowercaseHex(v[key])
+		}
+	}
+	return value
+}
+
+// dependsOnHead reports whether the result of a call moves with the chain
+// head, in which case nodes queried a few blocks apart legitimately differ
+func dependsOnHead(call *tooltypes.JsonrpcMessage) bool {
+	if call.Method == "eth_blockNumber" {
+		return true
+	}
+	return slices.ContainsFunc(call.Params, usesHeadBlockTag)
+}
+
+func usesHeadBlockTag(param interface{}) bool {
+	switch p := param.(type) {
+	case string:
+		return slices.Contains(headBlockTags, p)
+	case map[string]interface{}:
+		for _, value := range p {
+			if usesHeadBlockTag(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func countMismatches(mismatches []tooltypes.ResponseMismatch, node string) int {
+	count := 0
+	for _, mismatch := range mismatches {
+		if mismatch.Node == node {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,669 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rs/zerolog/log"
+	"github.com/unifralabs/unifra-benchmark-tool/benchmarker"
+	"github.com/unifralabs/unifra-benchmark-tool/config"
+	"github.com/unifralabs/unifra-benchmark-tool/constants"
+	"github.com/unifralabs/unifra-benchmark-tool/db"
+	"github.com/unifralabs/unifra-benchmark-tool/metrics"
+	"github.com/unifralabs/unifra-benchmark-tool/outputter"
+	"github.com/unifralabs/unifra-benchmark-tool/scenario"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(ctx context.Context, cfg *config.EnvConfig, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"rpc", "rpc [flags]", "run the RPC load test", runRpcCommand},
+		{"tx", "tx --type=eoa|erc20|erc721|blob|setcode|contract|sstore|... [flags]", "fund the test accounts and run a transaction test", runTxCommand},
+		{"fund", "fund --type=eoa|erc20|erc721|blob|setcode|contract|sstore|... [flags]", "fund the test accounts of a transaction test", runFundCommand},
+		{"report", "report [flags] [RESULTS_FILE]", "print the tables of saved RPC results", runReportCommand},
+		{"compare", "compare [flags] RESULTS_FILE...", "compare saved RPC results of several runs or nodes", runCompareCommand},
+		{"plot", "plot [flags] [RESULTS_FILE]", "plot saved RPC results", runPlotCommand},
+		{"run", "run [flags] SCENARIO_FILE", "run the benchmarks of a YAML or JSON scenario", runScenarioCommand},
+		{"validate", "validate SCENARIO_FILE...", "check scenario files for errors", runValidateCommand},
+		{"runs", "runs [flags]", "list the past runs of the history database", runRunsCommand},
+		{"show-run", "show-run RUN_ID", "print a past run of the history database", runShowRunCommand},
+		{"export-run", "export-run [flags] RUN_ID", "export a past run of the history database as JSON", runExportRunCommand},
+		{"gate", "gate [flags] BASELINE [RESULTS]", "fail when results regress against a baseline results file or run:ID", runGateCommand},
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [COMMAND] [flags]\n\n", filepath.Base(os.Args[0]))
+	fmt.Fprintln(os.Stderr, "Without a command, funds the accounts and runs the TX_TYPES transaction tests, then the RPC test.")
+	fmt.Fprintln(os.Stderr, "Flags override the values of the .env file and the environment.")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-48s %s\n", cmd.usage, cmd.description)
+	}
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		cmd, _ := findCommand(name)
+		fmt.Fprintf(fs.Output(), "Usage: %s %s\n\n", filepath.Base(os.Args[0]), cmd.usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func addNodeFlags(fs *flag.FlagSet, cfg *config.EnvConfig) {
+	fs.StringVar(&cfg.RpcUrl, "url", cfg.RpcUrl, "RPC URL of the node under test")
+	fs.StringVar(&cfg.NodeName, "node", cfg.NodeName, "name of the node under test")
+	fs.StringVar(&cfg.TestName, "name", cfg.TestName, "name of the test")
+	fs.StringVar(&cfg.OutputDir, "output", cfg.OutputDir, "output directory")
+}
+
+func runRpcCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	fs := newFlagSet("rpc")
+	addNodeFlags(fs, cfg)
+	fs.StringVar(&cfg.Nodes, "nodes", cfg.Nodes, "comma-separated NAME=URL nodes, instead of --node and --url")
+	fs.StringVar(&cfg.NodesMode, "nodes-mode", cfg.NodesMode, "how to run several nodes: sequential or simultaneous")
+	fs.IntVar(&cfg.NodesRepeats, "repeats", cfg.NodesRepeats, "number of times the load test is repeated")
+	fs.StringVar(&cfg.RpcWorkload, "workload", cfg.RpcWorkload, "weighted methods, e.g. eth_call=3,eth_getBalance=1")
+	fs.StringVar(&cfg.Network, "network", cfg.Network, "network of the sample datasets")
+	fs.StringVar(&cfg.SamplesDir, "samples-dir", cfg.SamplesDir, "directory of the sample datasets")
+	fs.IntVar(&cfg.SamplesHarvestBlocks, "harvest-blocks", cfg.SamplesHarvestBlocks, "harvest missing samples from this many recent blocks")
+	fs.StringVar(&cfg.BlockRange, "block-range", cfg.BlockRange, "blocks of historical calls: last:N, archive[:N], recent or START-END")
+	fs.StringVar(&cfg.LoadTestMode, "mode", cfg.LoadTestMode, "load test mode: stress, spike or soak")
+	fs.IntVar(&cfg.LoadTestBaseRate, "base-rate", cfg.LoadTestBaseRate, "base rate of the load test mode (rps)")
+	fs.IntVar(&cfg.LoadTestPeakRate, "peak-rate", cfg.LoadTestPeakRate, "peak rate of the stress and spike modes (rps)")
+	fs.IntVar(&cfg.LoadTestStep, "step", cfg.LoadTestStep, "rate increment of the stress mode (rps)")
+	fs.IntVar(&cfg.LoadTestStepDuration, "step-duration", cfg.LoadTestStepDuration, "duration of each stress step and spike baseline (s)")
+	fs.IntVar(&cfg.LoadTestBurstDuration, "burst-duration", cfg.LoadTestBurstDuration, "duration of the spike burst (s)")
+	fs.IntVar(&cfg.LoadTestDuration, "duration", cfg.LoadTestDuration, "duration of the soak mode (s)")
+	fs.IntVar(&cfg.LoadTestCheckpointInterval, "checkpoint-interval", cfg.LoadTestCheckpointInterval, "interval between soak checkpoints (s)")
+	fs.StringVar(&cfg.SaveLoadTest, "save-test", cfg.SaveLoadTest, "save the generated load test to this file (.gz to compress)")
+	fs.StringVar(&cfg.ReplayLoadTest, "replay", cfg.ReplayLoadTest, "replay a saved load test instead of generating one")
+	fs.BoolVar(&cfg.VerifyResponses, "verify", cfg.VerifyResponses, "compare the responses of the nodes to identical calls")
+	fs.StringVar(&cfg.VerifyReference, "reference", cfg.VerifyReference, "reference of the verification: a node name or an extra NAME=URL node")
+	fs.BoolVar(&cfg.CapacitySearch, "capacity-search", cfg.CapacitySearch, "search the max sustainable throughput")
+	fs.IntVar(&cfg.CapacityStartRate, "start-rate", cfg.CapacityStartRate, "first rate of the capacity search (rps)")
+	fs.IntVar(&cfg.CapacityMaxRate, "max-rate", cfg.CapacityMaxRate, "highest rate of the capacity search (rps)")
+	fs.IntVar(&cfg.CapacityPrecision, "precision", cfg.CapacityPrecision, "precision of the capacity search (rps)")
+	fs.IntVar(&cfg.CapacityProbeDuration, "probe-duration", cfg.CapacityProbeDuration, "duration of each capacity probe (s)")
+	fs.Float64Var(&cfg.SloMinSuccess, "slo-success", cfg.SloMinSuccess, "minimum success rate of the SLO")
+	fs.DurationVar(&cfg.SloMaxP99, "slo-p99", cfg.SloMaxP99, "maximum p99 latency of the SLO")
+	addMetricsAddrFlag(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := serveMetrics(cfg); err != nil {
+		return err
+	}
+
+	nodes, err := benchmarker.NodesFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	history, closeHistory := benchmarker.OpenHistory()
+	defer closeHistory()
+
+	rpcBenchmarker, err := benchmarker.NewRpcBenchmarker(cfg, nodes, history)
+	if err != nil {
+		return err
+	}
+	return rpcBenchmarker.Run()
+}
+
+// newTxBenchmarker parses the flags of a transaction command. The returned
+// database client, nil when the database cannot be opened, is for the caller
+// to close.
+func newTxBenchmarker(name string, cfg *config.EnvConfig, args []string) (*benchmarker.TxBenchmarker, *db.Client, error) {
+	fs := newFlagSet(name)
+	addNodeFlags(fs, cfg)
+	txType := fs.String("type", "eoa", "transaction type: eoa, erc20, erc721, blob, setcode, contract, or a stress workload: sstore, sload, keccak, calldata, logs or create")
+	fs.StringVar(&cfg.AdminAccountMnemonic, "mnemonic", cfg.AdminAccountMnemonic, "mnemonic of the funded admin account")
+	fs.IntVar(&cfg.NumTestAccounts, "accounts", cfg.NumTestAccounts, "number of test accounts")
+	fs.IntVar(&cfg.NumTransactions, "txs", cfg.NumTransactions, "number of transactions")
+	fs.IntVar(&cfg.SendTransactionBatchSize, "batch-size", cfg.SendTransactionBatchSize, "number of transactions per batch request")
+	fs.StringVar(&cfg.TxFeeMode, "fee-mode", cfg.TxFeeMode, "fee mode: legacy, access-list or dynamic-fee")
+	fs.IntVar(&cfg.BlobsPerTx, "blobs", cfg.BlobsPerTx, "blobs per blob transaction")
+	fs.Uint64Var(&cfg.StressGasPerTx, "gas-per-tx", cfg.StressGasPerTx, "gas used by each transaction of a stress workload")
+	fs.StringVar(&cfg.ContractAbi, "abi", cfg.ContractAbi, "ABI JSON file of the contract called by contract transactions")
+	fs.StringVar(&cfg.ContractBin, "bin", cfg.ContractBin, "hex bytecode file to deploy the contract")
+	fs.StringVar(&cfg.ContractAddress, "address", cfg.ContractAddress, "address of an already deployed contract, instead of --bin")
+	fs.StringVar(&cfg.ContractConstructorArgs, "constructor-args", cfg.ContractConstructorArgs, "comma-separated constructor argument templates")
+	fs.StringVar(&cfg.ContractMethod, "method", cfg.ContractMethod, "contract method called by each transaction")
+	fs.StringVar(&cfg.ContractArgs, "args", cfg.ContractArgs, "comma-separated method argument templates, e.g. {receiver},{random:1..1000}")
+	fs.StringVar(&cfg.ContractValue, "value", cfg.ContractValue, "wei sent with each contract call")
+	addMetricsAddrFlag(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	parsedTxType, err := tooltypes.ParseTxType(*txType)
+	if err != nil {
+		return nil, nil, err
+	}
+	feeMode, err := tooltypes.ParseFeeMode(cfg.TxFeeMode)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.AdminAccountMnemonic == "" {
+		return nil, nil, fmt.Errorf("%s requires a mnemonic (--mnemonic or ADMIN_ACCOUNT_MNEMONIC)", name)
+	}
+	if err := serveMetrics(cfg); err != nil {
+		return nil, nil, err
+	}
+
+	node, err := benchmarker.TxNodeFromConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := ethclient.Dial(node.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options := benchmarker.TxOptionsFromConfig(cfg, parsedTxType, feeMode)
+
+	// Without the database, runs are only not recorded and contracts deployed again
+	dbClient, err := db.NewClient()
+	if err != nil {
+		log.Warn().Msgf("Runs will not be recorded and contracts will not be reused: %v", err)
+	} else {
+		options.Contracts = dbClient
+		options.History = dbClient
+	}
+
+	txBenchmarker, err := benchmarker.NewTxBenchmarker(client, cfg.AdminAccountMnemonic, node, parsedTxType, options,
+		cfg.NumTestAccounts, cfg.NumTransactions, cfg.SendTransactionBatchSize, cfg.OutputDir)
+	if err != nil {
+		if dbClient != nil {
+			dbClient.Close()
+		}
+		return nil, nil, err
+	}
+	return txBenchmarker, dbClient, nil
+}
+
+func runTxCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	txBenchmarker, dbClient, err := newTxBenchmarker("tx", cfg, args)
+	if err != nil {
+		return err
+	}
+	if dbClient != nil {
+		defer dbClient.Close()
+	}
+
+	if err := untilCancelled(ctx, txBenchmarker.Initialize); err != nil {
+		return err
+	}
+	return untilCancelled(ctx, func() error {
+		_, err := txBenchmarker.Run()
+		return err
+	})
+}
+
+func runFundCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	txBenchmarker, dbClient, err := newTxBenchmarker("fund", cfg, args)
+	if err != nil {
+		return err
+	}
+	if dbClient != nil {
+		defer dbClient.Close()
+	}
+	return untilCancelled(ctx, txBenchmarker.Initialize)
+}
+
+// untilCancelled runs a stage, giving up on it when ctx is cancelled. The
+// transactions already sent by the stage are not waited for.
+func untilCancelled(ctx context.Context, stage func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- stage()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("interrupted: %w", ctx.Err())
+	}
+}
+
+func addMetricsFlag(fs *flag.FlagSet) *string {
+	return fs.String("metrics", strings.Join(outputter.DefaultReportMetrics, ","), "comma-separated metrics to print")
+}
+
+// resultsFileArg returns the results file given as argument, defaulting to
+// the RPC results of the output directory
+func resultsFileArg(fs *flag.FlagSet, cfg *config.EnvConfig) string {
+	if fs.NArg() > 0 {
+		return fs.Arg(0)
+	}
+	return filepath.Join(cfg.OutputDir, constants.RPC_OUTPUT_FILE)
+}
+
+func addMetricsAddrFlag(fs *flag.FlagSet, cfg *config.EnvConfig) {
+	fs.StringVar(&cfg.MetricsAddr, "metrics-addr", cfg.MetricsAddr, "serve the live progress as Prometheus metrics on this address, e.g. :9100")
+}
+
+// serveMetrics serves the Prometheus metrics if an address is configured
+func serveMetrics(cfg *config.EnvConfig) error {
+	if cfg.MetricsAddr == "" {
+		return nil
+	}
+	return metrics.Serve(cfg.MetricsAddr)
+}
+
+func runReportCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	fs := newFlagSet("report")
+	fs.StringVar(&cfg.OutputDir, "output", cfg.OutputDir, "output directory of the results")
+	metrics := addMetricsFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	payload, err := outputter.LoadSingleRunResults(resultsFileArg(fs, cfg))
+	if err != nil {
+		return err
+	}
+	return outputter.PrintLoadTestResults(payload.Results, nil, strings.Split(*metrics, ","), false)
+}
+
+func runCompareCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	fs := newFlagSet("compare")
+	metrics := addMetricsFlag(fs)
+	baseline := fs.String("baseline", "", "name of the baseline results (default: the first results)")
+	figuresDir := fs.String("figures", "", "directory of the comparison figures (default: comparison next to the first results file)")
+	noPlots := fs.Bool("no-plots", false, "skip the comparison figures")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("compare requires at least one results file")
+	}
+
+	results, names, nodes, err := outputter.LoadComparisonResults(fs.Args())
+	if err != nil {
+		return err
+	}
+	if *baseline != "" {
+		index := slices.Index(names, *baseline)
+		if index < 0 {
+			return fmt.Errorf("baseline %s not found in %s", *baseline, strings.Join(names, ", "))
+		}
+		names = append([]string{*baseline}, slices.Delete(names, index, index+1)...)
+	}
+
+	if err := outputter.PrintLoadTestResults(results, names, strings.Split(*metrics, ","), true); err != nil {
+		return err
+	}
+	if *noPlots {
+		return nil
+	}
+
+	if *figuresDir == "" {
+		*figuresDir = filepath.Join(filepath.Dir(fs.Arg(0)), "comparison")
+	}
+	colors := utils.GetNodesPlotColors(nodes)
+	if err := outputter.PlotComparison(results, names, nil, colors, cfg.TestName, *figuresDir); err != nil {
+		return err
+	}
+	log.Info().Msgf("Comparison figures saved to %s", *figuresDir)
+	return nil
+}
+
+func runPlotCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	fs := newFlagSet("plot")
+	fs.StringVar(&cfg.OutputDir, "output", cfg.OutputDir, "output directory of the results")
+	figuresDir := fs.String("figures", "", "directory of the figures (default: figures next to the results file)")
+	linear := fs.Bool("linear", false, "plot latencies on a linear scale")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := resultsFileArg(fs, cfg)
+	payload, err := outputter.LoadSingleRunResults(path)
+	if err != nil {
+		return err
+	}
+
+	if *figuresDir == "" {
+		*figuresDir = filepath.Join(filepath.Dir(path), "figures")
+	}
+	colors := utils.GetNodesPlotColors(payload.Nodes)
+	return outputter.PlotLoadTestResults(payload.Results, cfg.TestName, *figuresDir, !*linear, colors, "", "", true, true, true)
+}
+
+// loadScenario loads a scenario file and fails with every validation error
+func loadScenario(path string) (*tooltypes.Scenario, error) {
+	s, err := scenario.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := scenario.Validate(s)
+	for _, err := range errs {
+		log.Error().Msgf("%s: %v", path, err)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %d validation errors", path, len(errs))
+	}
+	return s, nil
+}
+
+func runValidateCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	fs := newFlagSet("validate")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("validate requires a scenario file")
+	}
+
+	failed := 0
+	for _, path := range fs.Args() {
+		if _, err := loadScenario(path); err != nil {
+			log.Error().Msgf("%v", err)
+			failed++
+			continue
+		}
+		log.Info().Msgf("✅ %s is valid", path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scenarios are invalid", failed, fs.NArg())
+	}
+	return nil
+}
+
+func runScenarioCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	fs := newFlagSet("run")
+	fs.StringVar(&cfg.OutputDir, "output", cfg.OutputDir, "output directory, unless set by the scenario")
+	fs.StringVar(&cfg.AdminAccountMnemonic, "mnemonic", cfg.AdminAccountMnemonic, "mnemonic of the funded admin account")
+	addMetricsAddrFlag(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("run requires a scenario file")
+	}
+
+	s, err := loadScenario(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := serveMetrics(cfg); err != nil {
+		return err
+	}
+
+	cfg.TestName = s.Name
+	if s.OutputDir != "" {
+		cfg.OutputDir = s.OutputDir
+	}
+
+	nodes, err := utils.ParseNodes(s.Nodes, false, true)
+	if err != nil {
+		return err
+	}
+
+	var history tooltypes.RunHistory
+	if s.Tx != nil {
+		if cfg.AdminAccountMnemonic == "" {
+			return fmt.Errorf("tx benchmarks require a mnemonic (--mnemonic or ADMIN_ACCOUNT_MNEMONIC)")
+		}
+		txType, err := tooltypes.ParseTxType(s.Tx.Type)
+		if err != nil {
+			return err
+		}
+		feeMode, err := tooltypes.ParseFeeMode(s.Tx.FeeMode)
+		if err != nil {
+			return err
+		}
+
+		// Transactions all go to the chain through the first node
+		node, err := utils.ParseNode(s.Nodes[0], false)
+		if err != nil {
+			return err
+		}
+		if parsed, ok := nodes[node.Name]; ok {
+			node = parsed
+		}
+		client, err := ethclient.Dial(node.URL)
+		if err != nil {
+			return err
+		}
+
+		dbClient, err := db.NewClient()
+		if err != nil {
+			return fmt.Errorf("error creating db client: %s", err)
+		}
+		defer dbClient.Close()
+		history = dbClient
+
+		options := tooltypes.TxOptions{FeeMode: feeMode, BlobsPerTx: s.Tx.BlobsPerTx, Contract: s.Tx.Contract, GasPerTx: s.Tx.GasPerTx, Contracts: dbClient, History: dbClient}
+		txBenchmarker, err := benchmarker.NewTxBenchmarker(client, cfg.AdminAccountMnemonic, node, txType, options,
+			s.Tx.Accounts, s.Tx.Transactions, s.Tx.BatchSize, cfg.OutputDir)
+		if err != nil {
+			return err
+		}
+		if err := txBenchmarker.Initialize(); err != nil {
+			return err
+		}
+		if _, err := txBenchmarker.Run(); err != nil {
+			return err
+		}
+	}
+
+	if s.Rpc != nil {
+		if history == nil {
+			var closeHistory func()
+			history, closeHistory = benchmarker.OpenHistory()
+			defer closeHistory()
+		}
+
+		rpcBenchmarker, err := benchmarker.NewRpcBenchmarker(cfg, nodes, history)
+		if err != nil {
+			return err
+		}
+		if err := rpcBenchmarker.RunScenario(*s.Rpc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runRunsCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	fs := newFlagSet("runs")
+	kind := fs.String("kind", "", "only list the runs of a kind: rpc, capacity or tx")
+	limit := fs.Int("limit", 20, "number of runs to list, 0 for all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	dbClient, err := db.NewClient()
+	if err != nil {
+		return fmt.Errorf("error creating db client: %s", err)
+	}
+	defer dbClient.Close()
+
+	runs, err := dbClient.ListRuns(tooltypes.RunKind(*kind), *limit)
+	if err != nil {
+		return err
+	}
+	outputter.PrintRuns(runs)
+	return nil
+}
+
+// runArg opens the database and returns the run given as argument
+func runArg(fs *flag.FlagSet) (*tooltypes.RunRecord, error) {
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return nil, fmt.Errorf("a run ID is required")
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run ID: %s", fs.Arg(0))
+	}
+
+	dbClient, err := db.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("error creating db client: %s", err)
+	}
+	defer dbClient.Close()
+
+	return dbClient.GetRun(id)
+}
+
+func runShowRunCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	fs := newFlagSet("show-run")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	run, err := runArg(fs)
+	if err != nil {
+		return err
+	}
+	outputter.PrintRun(run)
+	return nil
+}
+
+func runExportRunCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	fs := newFlagSet("export-run")
+	output := fs.String("o", "", "file to export the run to (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	run, err := runArg(fs)
+	if err != nil {
+		return err
+	}
+	return outputter.ExportRun(run, *output)
+}
+
+// loadRegressionResults loads the results of a results file, or of a run of
+// the history given as run:ID
+func loadRegressionResults(source string) (*outputter.RegressionResults, error) {
+	arg, isRun := strings.CutPrefix(source, "run:")
+	if !isRun {
+		return outputter.LoadRegressionResults(source)
+	}
+
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run ID: %s", arg)
+	}
+
+	dbClient, err := db.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("error creating db client: %s", err)
+	}
+	defer dbClient.Close()
+
+	run, err := dbClient.GetRun(id)
+	if err != nil {
+		return nil, err
+	}
+	return outputter.RegressionResultsFromRun(run)
+}
+
+func runGateCommand(ctx context.Context, cfg *config.EnvConfig, args []string) error {
+	fs := newFlagSet("gate")
+	fs.StringVar(&cfg.OutputDir, "output", cfg.OutputDir, "output directory of the results")
+	tolerancesFlag := fs.String("tolerances", "", fmt.Sprintf("comma-separated metric tolerances (default: %s for RPC results, %s for tx results)", outputter.DefaultRpcTolerances, outputter.DefaultTxTolerances))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return fmt.Errorf("gate requires a baseline and at most one results file or run")
+	}
+
+	baseline, err := loadRegressionResults(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to load baseline: %w", err)
+	}
+	source := filepath.Join(cfg.OutputDir, constants.RPC_OUTPUT_FILE)
+	if fs.NArg() == 2 {
+		source = fs.Arg(1)
+	} else if baseline.Kind == tooltypes.TxRun {
+		// Tx results default to the results file of the tx type of the baseline
+		for name := range baseline.Tx {
+			txType, err := tooltypes.ParseTxType(string(name))
+			if err != nil {
+				return fmt.Errorf("cannot tell the results file of the baseline %s, give it: %w", fs.Arg(0), err)
+			}
+			source = filepath.Join(cfg.OutputDir, constants.TxOutputFile(string(txType)))
+		}
+	}
+	current, err := loadRegressionResults(source)
+	if err != nil {
+		return fmt.Errorf("failed to load results: %w", err)
+	}
+
+	if *tolerancesFlag == "" {
+		*tolerancesFlag = outputter.DefaultRpcTolerances
+		if baseline.Kind == tooltypes.TxRun {
+			*tolerancesFlag = outputter.DefaultTxTolerances
+		}
+	}
+	tolerances, err := tooltypes.ParseTolerances(*tolerancesFlag)
+	if err != nil {
+		return err
+	}
+
+	checks, err := outputter.CheckRegressions(baseline, current, tolerances)
+	if err != nil {
+		return err
+	}
+	if len(checks) == 0 {
+		return fmt.Errorf("no metric of the baseline %s to check", fs.Arg(0))
+	}
+	outputter.PrintRegressionChecks(checks)
+
+	regressions := 0
+	for _, check := range checks {
+		if check.Regressed {
+			regressions++
+		}
+	}
+	if regressions > 0 {
+		return fmt.Errorf("%d of %d checks regressed against the baseline %s", regressions, len(checks), fs.Arg(0))
+	}
+	log.Info().Msgf("No regression against the baseline %s in %d checks", fs.Arg(0), len(checks))
+	return nil
+}
@@ -1,6 +1,11 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
 
 type EnvConfig struct {
 	TestName                 string `mapstructure:"TEST_NAME"`
@@ -8,8 +13,58 @@ type EnvConfig struct {
 	NumTestAccounts          int    `mapstructure:"NUM_TEST_ACCOUNTS"`
 	AdminAccountMnemonic     string `mapstructure:"ADMIN_ACCOUNT_MNEMONIC"`
 	RpcUrl                   string `mapstructure:"RPC_URL"`
+	Nodes                    string `mapstructure:"NODES"` // NAME=URL,... for RPC tests, instead of NODE_NAME and RPC_URL
+	NodesMode                string `mapstructure:"NODES_MODE"`
+	NodesRepeats             int    `mapstructure:"NODES_REPEATS"`
 	OutputDir                string `mapstructure:"OUTPUT_DIR"`
 	SendTransactionBatchSize int    `mapstructure:"SEND_TRANSACTION_BATCH_SIZE"`
+	TxTypes                  string `mapstructure:"TX_TYPES"`    // transaction benchmarks of a full run, e.g. eoa,erc20,erc721
+	TxFeeMode                string `mapstructure:"TX_FEE_MODE"` // legacy, access-list or dynamic-fee
+	BlobsPerTx               int    `mapstructure:"BLOBS_PER_TX"`
+	StressGasPerTx           uint64 `mapstructure:"STRESS_GAS_PER_TX"`
+	NumTransactions          int    `mapstructure:"NUM_TRANSACTIONS"`
+	RpcWorkload              string `mapstructure:"RPC_WORKLOAD"`
+	Network                  string `mapstructure:"NETWORK"`
+	SamplesDir               string `mapstructure:"SAMPLES_DIR"`
+	SamplesHarvestBlocks     int    `mapstructure:"SAMPLES_HARVEST_BLOCKS"`
+	BlockRange               string `mapstructure:"BLOCK_RANGE"`
+	SaveLoadTest             string `mapstructure:"SAVE_LOAD_TEST"`
+	ReplayLoadTest           string `mapstructure:"REPLAY_LOAD_TEST"`
+
+	// Load test mode (stress, spike or soak) and its parameters
+	LoadTestMode               string `mapstructure:"LOAD_TEST_MODE"`
+	LoadTestBaseRate           int    `mapstructure:"LOAD_TEST_BASE_RATE"`
+	LoadTestPeakRate           int    `mapstructure:"LOAD_TEST_PEAK_RATE"`
+	LoadTestStep               int    `mapstructure:"LOAD_TEST_STEP"`
+	LoadTestStepDuration       int    `mapstructure:"LOAD_TEST_STEP_DURATION"`
+	LoadTestBurstDuration      int    `mapstructure:"LOAD_TEST_BURST_DURATION"`
+	LoadTestDuration           int    `mapstructure:"LOAD_TEST_DURATION"`
+	LoadTestCheckpointInterval int    `mapstructure:"LOAD_TEST_CHECKPOINT_INTERVAL"`
+
+	// Max sustainable throughput search and its SLO
+	CapacitySearch        bool          `mapstructure:"CAPACITY_SEARCH"`
+	CapacityStartRate     int           `mapstructure:"CAPACITY_START_RATE"`
+	CapacityMaxRate       int           `mapstructure:"CAPACITY_MAX_RATE"`
+	CapacityPrecision     int           `mapstructure:"CAPACITY_PRECISION"`
+	CapacityProbeDuration int           `mapstructure:"CAPACITY_PROBE_DURATION"`
+	SloMinSuccess         float64       `mapstructure:"SLO_MIN_SUCCESS"`
+	SloMaxP99             time.Duration `mapstructure:"SLO_MAX_P99"`
+
+	// Comparison of the responses of the nodes, against a reference node if set
+	VerifyResponses bool   `mapstructure:"VERIFY_RESPONSES"`
+	VerifyReference string `mapstructure:"VERIFY_REFERENCE"`
+
+	// Contract called by CONTRACT transactions, its arguments being templates
+	ContractAbi             string `mapstructure:"CONTRACT_ABI"`
+	ContractBin             string `mapstructure:"CONTRACT_BIN"`
+	ContractAddress         string `mapstructure:"CONTRACT_ADDRESS"`
+	ContractConstructorArgs string `mapstructure:"CONTRACT_CONSTRUCTOR_ARGS"`
+	ContractMethod          string `mapstructure:"CONTRACT_METHOD"`
+	ContractArgs            string `mapstructure:"CONTRACT_ARGS"`
+	ContractValue           string `mapstructure:"CONTRACT_VALUE"`
+
+	// Address of the Prometheus /metrics endpoint of the live progress, e.g. :9100
+	MetricsAddr string `mapstructure:"METRICS_ADDR"`
 }
 
 // Load config file via viper
@@ -18,7 +73,12 @@ func LoadEnvConfig() (*EnvConfig, error) {
 	viper.SetConfigFile(".env")
 	viper.ReadInConfig()
 
-	cfg := EnvConfig{}
+	cfg := EnvConfig{
+		TxTypes:         "eoa",
+		NumTransactions: 60,
+		BlobsPerTx:      1,
+		StressGasPerTx:  tooltypes.DefaultGasPerTx,
+	}
 	err := viper.Unmarshal(&cfg)
 	if err != nil {
 		return nil, err
@@ -1,6 +1,16 @@
 package constants
 
+import "strings"
+
 const (
 	RPC_OUTPUT_FILE = "rpc_results.json"
-	EOA_OUTPUT_FILE = "eoa_results.json"
+
+	CAPACITY_OUTPUT_FILE     = "capacity_results.json"
+	VERIFICATION_OUTPUT_FILE = "verification_results.json"
 )
+
+// TxOutputFile is the results file of a transaction benchmark type, e.g.
+// eoa_results.json
+func TxOutputFile(txType string) string {
+	return strings.ToLower(txType) + "_results.json"
+}
@@ -0,0 +1,15 @@
+// Package delegate holds the code that accounts delegate to in set code
+// transactions. It is small enough to be assembled by hand.
+package delegate
+
+// Bin is the creation code of a counter: every call increments the counter
+// in slot 0 of the storage of the executing account, which is the delegating
+// account when the code runs through an EIP-7702 delegation.
+//
+//	PUSH10 <runtime> PUSH1 0x00 MSTORE PUSH1 0x0a PUSH1 0x16 RETURN
+const Bin = "0x6960005460010160005500600052600a6016f3"
+
+// RuntimeBin is the code deployed by Bin:
+//
+//	PUSH1 0x00 SLOAD PUSH1 0x01 ADD PUSH1 0x00 SSTORE STOP
+const RuntimeBin = "0x60005460010160005500"
@@ -0,0 +1,51 @@
+// Package stress holds the contracts of the execution-layer stress workloads.
+// They are small enough to be assembled by hand. Each one repeats its
+// operation as many times as the 32-byte word its calldata starts with.
+package stress
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Kind is the operation a stress contract repeats
+type Kind string
+
+const (
+	SStore   Kind = "sstore"
+	SLoad    Kind = "sload"
+	Keccak   Kind = "keccak"
+	Calldata Kind = "calldata"
+	Logs     Kind = "logs"
+	Create   Kind = "create"
+)
+
+// RuntimeBins are the deployed code of the stress contracts. Every loop keeps
+// N, then the counter i, on top of the stack:
+//
+//	PUSH1 0x00 CALLDATALOAD PUSH1 0x00
+//	loop: JUMPDEST DUP2 DUP2 LT ISZERO PUSH1 end JUMPI <op> PUSH1 0x01 ADD PUSH1 loop JUMP
+//	end: JUMPDEST
+var RuntimeBins = map[Kind]string{
+	// Writes fresh slots from the offset kept in slot 0, then moves the offset
+	// past them, so that each write sets a zero slot:
+	//	PUSH1 0x00 SLOAD <loop: NUMBER DUP2 DUP5 ADD PUSH1 0x01 ADD SSTORE> POP ADD PUSH1 0x00 SSTORE STOP
+	SStore: "0x60005460003560005b81811015601e5743818401600101556001016008565b500160005500",
+	// Reads the cold slots 0 to N-1: <loop: DUP1 SLOAD POP> STOP
+	SLoad: "0x60003560005b818110156016578054506001016005565b00",
+	// Chains hashes of the first memory word: <loop: PUSH1 0x20 PUSH1 0x00 KECCAK256 PUSH1 0x00 MSTORE> STOP
+	Keccak: "0x60003560005b81811015601b5760206000206000526001016005565b00",
+	// Only pays for its calldata, N being the number of calldata words: STOP
+	Calldata: "0x00",
+	// Emits logs of 256 bytes: <loop: DUP1 PUSH2 0x0100 PUSH1 0x00 LOG1> STOP
+	Logs: "0x60003560005b81811015601a57806101006000a16001016005565b00",
+	// Creates contracts of 32 bytes of code, from the init code PUSH1 0x20 PUSH1 0x00 RETURN:
+	//	PUSH5 0x60206000f3 PUSH1 0x00 MSTORE <loop: PUSH1 0x05 PUSH1 0x1b PUSH1 0x00 CREATE POP> STOP
+	Create: "0x6460206000f360005260003560005b818110156024576005601b6000f050600101600e565b00",
+}
+
+// CreationCode returns the code deploying a runtime code of less than 256 bytes:
+//
+//	PUSH1 <len> DUP1 PUSH1 0x0b PUSH1 0x00 CODECOPY PUSH1 0x00 RETURN <runtime>
+func CreationCode(kind Kind) []byte {
+	runtime := common.FromHex(RuntimeBins[kind])
+	code := []byte{0x60, byte(len(runtime)), 0x80, 0x60, 0x0b, 0x60, 0x00, 0x39, 0x60, 0x00, 0xf3}
+	return append(code, runtime...)
+}
@@ -2,8 +2,10 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 
 	_ "github.com/mattn/go-sqlite3"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
 )
 
 type Client struct {
@@ -17,6 +19,10 @@ func NewClient() (*Client, error) {
 	}
 
 	client := &Client{db: db}
+	err = client.migrate()
+	if err != nil {
+		return nil, err
+	}
 	// err = client.CreateAccountsTable()
 	// if err != nil {
 	// 	return nil, err
@@ -28,6 +34,25 @@ func (c *Client) Close() error {
 	return c.db.Close()
 }
 
+func (c *Client) GetContract(chainID, deployer, name string) (*tooltypes.DeployedContract, error) {
+	contract := tooltypes.DeployedContract{ChainID: chainID, Deployer: deployer, Name: name}
+	err := c.db.QueryRow("SELECT address, bin_hash, code_hash FROM contracts WHERE chain_id = ? AND deployer = ? AND name = ?",
+		chainID, deployer, name).Scan(&contract.Address, &contract.BinHash, &contract.CodeHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &contract, nil
+}
+
+func (c *Client) SaveContract(contract tooltypes.DeployedContract) error {
+	_, err := c.db.Exec("INSERT OR REPLACE INTO contracts (chain_id, deployer, name, address, bin_hash, code_hash) VALUES (?, ?, ?, ?, ?, ?)",
+		contract.ChainID, contract.Deployer, contract.Name, contract.Address, contract.BinHash, contract.CodeHash)
+	return err
+}
+
 // func (c *Client) CreateAccountsTable() error {
 // 	_, err := c.db.Exec(`
 // 		CREATE TABLE IF NOT EXISTS accounts (
@@ -0,0 +1,236 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// SaveRun records a run with its nodes and results, and returns its ID
+func (c *Client) SaveRun(run tooltypes.RunRecord) (int64, error) {
+	cliArgs, err := json.Marshal(run.CLIArgs)
+	if err != nil {
+		return 0, err
+	}
+	parameters := run.Parameters
+	if parameters == nil {
+		parameters = json.RawMessage("null")
+	}
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO runs (kind, test_name, t_run_start, t_run_end, cli_args, parameters) VALUES (?, ?, ?, ?, ?, ?)",
+		run.Kind, run.TestName, run.TRunStart, run.TRunEnd, string(cliArgs), string(parameters))
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, node := range run.Nodes {
+		network, err := json.Marshal(node.Network)
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec("INSERT INTO run_nodes (run_id, name, url, remote, client_version, network) VALUES (?, ?, ?, ?, ?, ?)",
+			id, node.Name, node.URL, node.Remote, node.ClientVersion, string(network))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for _, m := range run.Attacks {
+		_, err = tx.Exec(`INSERT INTO attack_metrics (run_id, node, attack, target_rate, target_duration, requests,
+			actual_rate, throughput, success, min, mean, p50, p90, p95, p99, max) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, m.Node, m.Attack, m.TargetRate, m.TargetDuration, m.Requests,
+			m.ActualRate, m.Throughput, m.Success, m.Min, m.Mean, m.P50, m.P90, m.P95, m.P99, m.Max)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if run.Tx != nil {
+		_, err = tx.Exec("INSERT INTO tx_results (run_id, tx_type, tps, mined_txs) VALUES (?, ?, ?, ?)",
+			id, run.Tx.TxType, run.Tx.TPS, run.Tx.MinedTxs)
+		if err != nil {
+			return 0, err
+		}
+		for _, b := range run.Tx.Blocks {
+			_, err = tx.Exec(`INSERT INTO tx_blocks (run_id, block_num, created_at, num_txs, gas_used, gas_limit,
+				gas_utilization, block_time, num_blobs, blob_gas_used, excess_blob_gas) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				id, b.BlockNum, b.CreatedAt, b.NumTxs, b.GasUsed, b.GasLimit,
+				b.GasUtilization, b.BlockTime, b.NumBlobs, b.BlobGasUsed, b.ExcessBlobGas)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return id, tx.Commit()
+}
+
+// ListRuns returns the most recent runs first, with their nodes but without
+// their results. A limit of 0 returns every run.
+func (c *Client) ListRuns(kind tooltypes.RunKind, limit int) ([]tooltypes.RunRecord, error) {
+	query := "SELECT id, kind, test_name, t_run_start, t_run_end, cli_args, parameters FROM runs"
+	args := []interface{}{}
+	if kind != "" {
+		query += " WHERE kind = ?"
+		args = append(args, kind)
+	}
+	query += " ORDER BY id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []tooltypes.RunRecord{}
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range runs {
+		if runs[i].Nodes, err = c.getRunNodes(runs[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return runs, nil
+}
+
+// GetRun returns a run with all its results
+func (c *Client) GetRun(id int64) (*tooltypes.RunRecord, error) {
+	run, err := scanRun(c.db.QueryRow("SELECT id, kind, test_name, t_run_start, t_run_end, cli_args, parameters FROM runs WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no run %d in the history", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if run.Nodes, err = c.getRunNodes(id); err != nil {
+		return nil, err
+	}
+	if run.Attacks, err = c.getRunAttacks(id); err != nil {
+		return nil, err
+	}
+	if run.Tx, err = c.getRunTxResults(id); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRun(row rowScanner) (*tooltypes.RunRecord, error) {
+	var run tooltypes.RunRecord
+	var cliArgs, parameters string
+	err := row.Scan(&run.ID, &run.Kind, &run.TestName, &run.TRunStart, &run.TRunEnd, &cliArgs, &parameters)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(cliArgs), &run.CLIArgs); err != nil {
+		return nil, fmt.Errorf("invalid cli args of run %d: %v", run.ID, err)
+	}
+	run.Parameters = json.RawMessage(parameters)
+	return &run, nil
+}
+
+func (c *Client) getRunNodes(id int64) (tooltypes.Nodes, error) {
+	rows, err := c.db.Query("SELECT name, url, remote, client_version, network FROM run_nodes WHERE run_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := tooltypes.Nodes{}
+	for rows.Next() {
+		var node tooltypes.Node
+		var network string
+		if err := rows.Scan(&node.Name, &node.URL, &node.Remote, &node.ClientVersion, &network); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(network), &node.Network); err != nil {
+			return nil, fmt.Errorf("invalid network of node %s: %v", node.Name, err)
+		}
+		nodes[node.Name] = node
+	}
+	return nodes, rows.Err()
+}
+
+func (c *Client) getRunAttacks(id int64) ([]tooltypes.AttackMetrics, error) {
+	rows, err := c.db.Query(`SELECT node, attack, target_rate, target_duration, requests,
+		actual_rate, throughput, success, min, mean, p50, p90, p95, p99, max
+		FROM attack_metrics WHERE run_id = ? ORDER BY node, attack`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attacks := []tooltypes.AttackMetrics{}
+	for rows.Next() {
+		var m tooltypes.AttackMetrics
+		err := rows.Scan(&m.Node, &m.Attack, &m.TargetRate, &m.TargetDuration, &m.Requests,
+			&m.ActualRate, &m.Throughput, &m.Success, &m.Min, &m.Mean, &m.P50, &m.P90, &m.P95, &m.P99, &m.Max)
+		if err != nil {
+			return nil, err
+		}
+		attacks = append(attacks, m)
+	}
+	return attacks, rows.Err()
+}
+
+func (c *Client) getRunTxResults(id int64) (*tooltypes.TxRunResults, error) {
+	var results tooltypes.TxRunResults
+	err := c.db.QueryRow("SELECT tx_type, tps, mined_txs FROM tx_results WHERE run_id = ?", id).
+		Scan(&results.TxType, &results.TPS, &results.MinedTxs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.db.Query(`SELECT block_num, created_at, num_txs, gas_used, gas_limit,
+		gas_utilization, block_time, num_blobs, blob_gas_used, excess_blob_gas
+		FROM tx_blocks WHERE run_id = ? ORDER BY block_num`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results.Blocks = []tooltypes.TxBlock{}
+	for rows.Next() {
+		var b tooltypes.TxBlock
+		err := rows.Scan(&b.BlockNum, &b.CreatedAt, &b.NumTxs, &b.GasUsed, &b.GasLimit,
+			&b.GasUtilization, &b.BlockTime, &b.NumBlobs, &b.BlobGasUsed, &b.ExcessBlobGas)
+		if err != nil {
+			return nil, err
+		}
+		results.Blocks = append(results.Blocks, b)
+	}
+	return &results, rows.Err()
+}
@@ -0,0 +1,123 @@
+package db
+
+import "fmt"
+
+// migrations are the versions of the schema, each one applied once in order.
+// Never edit a released migration, append a new one instead.
+var migrations = []string{
+	// 1: deployed benchmark contracts
+	`
+	CREATE TABLE IF NOT EXISTS contracts (
+		chain_id TEXT NOT NULL,
+		deployer TEXT NOT NULL,
+		name TEXT NOT NULL,
+		address TEXT NOT NULL,
+		bin_hash TEXT NOT NULL,
+		code_hash TEXT NOT NULL,
+		PRIMARY KEY (chain_id, deployer, name)
+	);
+	`,
+	// 2: run history
+	`
+	CREATE TABLE runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		test_name TEXT NOT NULL,
+		t_run_start INTEGER NOT NULL,
+		t_run_end INTEGER NOT NULL,
+		cli_args TEXT NOT NULL,
+		parameters TEXT NOT NULL
+	);
+	CREATE TABLE run_nodes (
+		run_id INTEGER NOT NULL REFERENCES runs (id),
+		name TEXT NOT NULL,
+		url TEXT NOT NULL,
+		remote TEXT NOT NULL,
+		client_version TEXT NOT NULL,
+		network TEXT NOT NULL,
+		PRIMARY KEY (run_id, name)
+	);
+	CREATE TABLE attack_metrics (
+		run_id INTEGER NOT NULL REFERENCES runs (id),
+		node TEXT NOT NULL,
+		attack INTEGER NOT NULL,
+		target_rate INTEGER NOT NULL,
+		target_duration INTEGER NOT NULL,
+		requests INTEGER NOT NULL,
+		actual_rate REAL,
+		throughput REAL,
+		success REAL,
+		min REAL,
+		mean REAL,
+		p50 REAL,
+		p90 REAL,
+		p95 REAL,
+		p99 REAL,
+		max REAL,
+		PRIMARY KEY (run_id, node, attack)
+	);
+	CREATE TABLE tx_results (
+		run_id INTEGER PRIMARY KEY REFERENCES runs (id),
+		tx_type TEXT NOT NULL,
+		tps REAL NOT NULL,
+		mined_txs INTEGER NOT NULL
+	);
+	CREATE TABLE tx_blocks (
+		run_id INTEGER NOT NULL REFERENCES runs (id),
+		block_num INTEGER NOT NULL,
+		created_at INTEGER NOT NULL,
+		num_txs INTEGER NOT NULL,
+		gas_used INTEGER NOT NULL,
+		gas_limit INTEGER NOT NULL,
+		gas_utilization REAL NOT NULL,
+		block_time INTEGER NOT NULL,
+		num_blobs INTEGER NOT NULL,
+		blob_gas_used INTEGER NOT NULL,
+		excess_blob_gas INTEGER NOT NULL,
+		PRIMARY KEY (run_id, block_num)
+	);
+	`,
+}
+
+// SchemaVersion is the version of the schema of this release
+var SchemaVersion = len(migrations)
+
+// migrate brings the schema of the database up to SchemaVersion
+func (c *Client) migrate() error {
+	_, err := c.db.Exec("CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)")
+	if err != nil {
+		return err
+	}
+
+	version := 0
+	err = c.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
+	if err != nil {
+		return err
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("database schema version %d is newer than this release (%d)", version, SchemaVersion)
+	}
+
+	for ; version < SchemaVersion; version++ {
+		tx, err := c.db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[version]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error migrating database to version %d: %v", version+1, err)
+		}
+		if _, err := tx.Exec("DELETE FROM schema_version"); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec("INSERT INTO schema_version (version) VALUES (?)", version+1); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error migrating database to version %d: %v", version+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
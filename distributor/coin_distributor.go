@@ -22,6 +22,7 @@ import (
 type RuntimeCosts struct {
 	AccDistributionCost *big.Int
 	SubAccount          *big.Int
+	Fees                *tooltypes.TxFees
 }
 
 type Distributor struct {
@@ -30,19 +31,20 @@ type Distributor struct {
 	provider             *ethclient.Client
 	rpcClient            *rpc_client.RpcClient
 	runtimeEstimator     tooltypes.TxBuilder
+	fees                 *tooltypes.TxFees
 	totalTx              int
 	requestedSubAccounts int
 	readyMnemonicIndexes []int
 }
 
-func NewDistributor(mnemonic string, subAccounts, totalTx int, runtimeEstimator tooltypes.TxBuilder, url string) (*Distributor, error) {
+func NewDistributor(mnemonic string, subAccounts, totalTx int, runtimeEstimator tooltypes.TxBuilder, fees *tooltypes.TxFees, url string) (*Distributor, error) {
 
 	client, err := ethclient.Dial(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to the Ethereum client: %v", err)
 	}
 
-	chainID, err := client.NetworkID(context.Background())
+	chainID, err := client.ChainID(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +70,7 @@ func NewDistributor(mnemonic string, subAccounts, totalTx int, runtimeEstimator
 		provider:             client,
 		rpcClient:            rpcClient,
 		runtimeEstimator:     runtimeEstimator,
+		fees:                 fees,
 		totalTx:              totalTx,
 		requestedSubAccounts: subAccounts,
 		readyMnemonicIndexes: []int{},
@@ -120,14 +123,16 @@ func (d *Distributor) calculateRuntimeCosts() (*RuntimeCosts, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to estimate base transaction: %v", err)
 	}
-	baseGasPrice, err := d.rpcClient.GetGasPrice()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %v", err)
-	}
-
-	baseTxCost := new(big.Int).Mul(baseGasPrice, baseTxEstimate)
+	// Budget for the max fee, dynamic fee transactions can be charged up to their fee cap
+	baseTxCost := new(big.Int).Mul(d.fees.MaxGasPrice(), baseTxEstimate)
 	baseTxCost.Add(baseTxCost, inherentValue)
 
+	// Blob transactions also pay for their blob gas, up to the blob fee cap
+	if blobBuilder, ok := d.runtimeEstimator.(tooltypes.BlobTxBuilder); ok {
+		blobCost := new(big.Int).Mul(d.fees.BlobFeeCap, new(big.Int).SetUint64(blobBuilder.GetBlobGas()))
+		baseTxCost.Add(baseTxCost, blobCost)
+	}
+
 	toAddress, err := utils.DeriveAddressFromMnemonic(d.mnemonic, 1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive address: %v", err)
@@ -147,6 +152,7 @@ func (d *Distributor) calculateRuntimeCosts() (*RuntimeCosts, error) {
 	return &RuntimeCosts{
 		AccDistributionCost: big.NewInt(int64(singleDistributionCost)),
 		SubAccount:          subAccountCost,
+		Fees:                d.fees,
 	}, nil
 }
 
@@ -225,6 +231,7 @@ func (d *Distributor) fundAccounts(costs *RuntimeCosts, accounts []*DistributeAc
 	log.Info().Msg("Funding accounts...")
 	bar := progressbar.Default(int64(len(accounts)))
 
+	utils.ApplyFees(d.ethWallet, costs.Fees)
 	for _, acc := range accounts {
 		log.Info().Msgf("Funding account %s with coins %s", acc.Address.Hex(), utils.FormatEther(acc.MissingFunds))
 		tx, err := d.rpcClient.BuildTransferTx(acc.Address, acc.MissingFunds, costs.AccDistributionCost.Uint64(), d.ethWallet)
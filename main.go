@@ -2,9 +2,10 @@ package main
 
 import (
 	"context"
-	"flag"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/ethereum/go-ethereum/crypto"
@@ -16,8 +17,6 @@ import (
 )
 
 func main() {
-	flag.Parse()
-
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	cfg, err := config.LoadEnvConfig()
 	if err != nil {
@@ -25,6 +24,46 @@ func main() {
 		return
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	sigCh := make(chan os.Signal, 1)
+	// Notify the sigCh channel when the program receives the interrupt (Ctrl+C) or termination signal.
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
+
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	if len(os.Args) > 1 && (os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help") {
+		printUsage()
+		return
+	}
+
+	// Without a subcommand, run every stage as before
+	if len(os.Args) < 2 {
+		runAll(ctx, cfg)
+		return
+	}
+	if strings.HasPrefix(os.Args[1], "-") {
+		fmt.Fprintf(os.Stderr, "unknown flag %q: flags follow a command, the full run is configured by the .env file and the environment\n\n", os.Args[1])
+		printUsage()
+		os.Exit(2)
+	}
+
+	cmd, ok := findCommand(os.Args[1])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		printUsage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(ctx, cfg, os.Args[2:]); err != nil {
+		log.Error().Msgf("%s: %v", cmd.name, err)
+		os.Exit(1)
+	}
+}
+
+func runAll(ctx context.Context, cfg *config.EnvConfig) {
 	_, ecdsaPrivateKey, err := utils.DerivePrivateKeyFromMnemonic(cfg.AdminAccountMnemonic, 0)
 	if err != nil {
 		log.Info().Msgf("failed to derive private key: %v", err)
@@ -36,25 +75,15 @@ func main() {
 
 	log.Info().Msgf("Config loaded: %v", cfg)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	sigCh := make(chan os.Signal, 1)
-	// Notify the sigCh channel when the program receives the interrupt (Ctrl+C) or termination signal.
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
-
-	go func() {
-		<-sigCh
-		cancel()
-	}()
+	if err := serveMetrics(cfg); err != nil {
+		log.Info().Msgf("Error serving metrics: %s", err)
+		return
+	}
 
 	benchmarker, err := benchmarker.NewBenchmarker(cfg)
 	if err != nil {
 		log.Info().Msgf("Error creating Benchmarker object: %s", err)
 		return
 	}
-	err = benchmarker.Initialize()
-	if err != nil {
-		log.Info().Msgf("Error initializing Benchmarker: %s", err)
-		return
-	}
 	benchmarker.RunBenchmarks(ctx)
 }
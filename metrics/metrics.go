@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+)
+
+const namespace = "unifra"
+
+var (
+	registry = prometheus.NewRegistry()
+	enabled  atomic.Bool
+
+	rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "requests_total",
+		Help:      "JSON-RPC requests sent by the attacks, by node and method.",
+	}, []string{"node", "method"})
+	rpcLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the JSON-RPC requests, by node and method.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, []string{"node", "method"})
+	rpcErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "errors_total",
+		Help:      "Failed JSON-RPC requests, by node, method and code: the JSON-RPC error code, the HTTP status, invalid_json, empty_result or transport.",
+	}, []string{"node", "method", "code"})
+	rpcTargetRate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "target_rate",
+		Help:      "Request rate of the current attack of each node, in requests per second.",
+	}, []string{"node"})
+
+	txSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tx",
+		Name:      "sent_total",
+		Help:      "Transactions accepted by the node, by tx type.",
+	}, []string{"type"})
+	txIncluded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tx",
+		Name:      "included_total",
+		Help:      "Transactions included in a block, by tx type.",
+	}, []string{"type"})
+	txPending = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "tx",
+		Name:      "pending",
+		Help:      "Transactions sent but not yet included, by tx type.",
+	}, []string{"type"})
+	txTPS = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "tx",
+		Name:      "tps",
+		Help:      "Transactions of the benchmark per second of block time, in the latest block.",
+	}, []string{"type"})
+	blockGasUsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "tx",
+		Name:      "block_gas_used",
+		Help:      "Gas used by the latest block during a transaction benchmark.",
+	}, []string{"type"})
+	blockTxs = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "tx",
+		Name:      "block_transactions",
+		Help:      "Transactions of the benchmark included per block, by tx type.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{"type"})
+)
+
+func init() {
+	registry.MustRegister(
+		rpcRequests, rpcLatency, rpcErrors, rpcTargetRate,
+		txSent, txIncluded, txPending, txTPS, blockGasUsed, blockTxs,
+	)
+}
+
+// Serve exposes the metrics on the /metrics endpoint of addr, e.g. :9100,
+// for the lifetime of the process. The benchmarks only record metrics once
+// the endpoint is served.
+func Serve(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for metrics on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Msgf("Metrics endpoint stopped: %v", err)
+		}
+	}()
+
+	enabled.Store(true)
+	log.Info().Msgf("Serving metrics on http://%s/metrics", listener.Addr())
+	return nil
+}
+
+// Enabled tells whether the metrics are served, and so worth recording
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// ObserveRpcRequest records a JSON-RPC request of an attack. code is empty
+// for successful requests.
+func ObserveRpcRequest(node, method string, latency time.Duration, code string) {
+	rpcRequests.WithLabelValues(node, method).Inc()
+	rpcLatency.WithLabelValues(node, method).Observe(latency.Seconds())
+	if code != "" {
+		rpcErrors.WithLabelValues(node, method, code).Inc()
+	}
+}
+
+// SetRpcTargetRate records the rate of the attack starting on a node
+func SetRpcTargetRate(node string, rate int) {
+	rpcTargetRate.WithLabelValues(node).Set(float64(rate))
+}
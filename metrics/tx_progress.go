@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rs/zerolog/log"
+)
+
+// pollInterval is how often the progress of the transactions is checked
+const pollInterval = time.Second
+
+// TxProgress follows the transactions of a benchmark from their sending to
+// their inclusion, by watching the new blocks of the chain. A nil TxProgress,
+// as returned when the metrics are not served, records nothing.
+type TxProgress struct {
+	client *ethclient.Client
+	txType string
+
+	mu        sync.Mutex
+	hashes    map[common.Hash]struct{}
+	watching  bool
+	sent      int
+	included  int
+	lastBlock uint64
+	lastTime  uint64
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// blockSummary holds the fields of a block that the progress needs, without
+// decoding its transactions
+type blockSummary struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Transactions []common.Hash  `json:"transactions"`
+}
+
+// NewTxProgress starts following a benchmark from the latest block of the chain
+func NewTxProgress(client *ethclient.Client, txType string) (*TxProgress, error) {
+	if !Enabled() {
+		return nil, nil
+	}
+
+	header, err := client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	txPending.WithLabelValues(txType).Set(0)
+	return &TxProgress{
+		client:    client,
+		txType:    txType,
+		hashes:    make(map[common.Hash]struct{}),
+		lastBlock: header.Number.Uint64(),
+		lastTime:  header.Time,
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}, nil
+}
+
+// Track starts watching the blocks for the signed transactions about to be sent
+func (p *TxProgress) Track(txs []*types.Transaction) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	for _, tx := range txs {
+		p.hashes[tx.Hash()] = struct{}{}
+	}
+	p.watching = true
+	p.mu.Unlock()
+
+	go p.watch()
+}
+
+// Sent records transactions accepted by the node
+func (p *TxProgress) Sent(n int) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent += n
+	txSent.WithLabelValues(p.txType).Add(float64(n))
+	txPending.WithLabelValues(p.txType).Set(float64(max(p.sent-p.included, 0)))
+}
+
+// Stop checks the blocks a last time and stops watching them
+func (p *TxProgress) Stop() {
+	if p == nil {
+		return
+	}
+
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+	close(p.done)
+
+	p.mu.Lock()
+	watching := p.watching
+	p.mu.Unlock()
+	if watching {
+		<-p.stopped
+	}
+}
+
+func (p *TxProgress) watch() {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			p.poll()
+			return
+		case <-ticker.C:
+			p.poll()
+		}
+	}
+}
+
+// poll counts the transactions of the blocks mined since the last poll
+func (p *TxProgress) poll() {
+	ctx := context.Background()
+	latest, err := p.client.BlockNumber(ctx)
+	if err != nil {
+		log.Debug().Msgf("Failed to fetch the latest block for the metrics: %v", err)
+		return
+	}
+
+	for number := p.lastBlock + 1; number <= latest; number++ {
+		var block *blockSummary
+		err := p.client.Client().CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+		if err != nil || block == nil {
+			log.Debug().Msgf("Failed to fetch block %d for the metrics: %v", number, err)
+			return
+		}
+		p.recordBlock(block)
+	}
+}
+
+func (p *TxProgress) recordBlock(block *blockSummary) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	included := 0
+	for _, hash := range block.Transactions {
+		if _, ok := p.hashes[hash]; ok {
+			delete(p.hashes, hash)
+			included++
+		}
+	}
+	p.included += included
+
+	txIncluded.WithLabelValues(p.txType).Add(float64(included))
+	txPending.WithLabelValues(p.txType).Set(float64(max(p.sent-p.included, 0)))
+	blockGasUsed.WithLabelValues(p.txType).Set(float64(block.GasUsed))
+	if included > 0 {
+		blockTxs.WithLabelValues(p.txType).Observe(float64(included))
+	}
+	if timestamp := uint64(block.Timestamp); timestamp > p.lastTime {
+		txTPS.WithLabelValues(p.txType).Set(float64(included) / float64(timestamp-p.lastTime))
+	}
+
+	p.lastBlock = uint64(block.Number)
+	p.lastTime = uint64(block.Timestamp)
+}
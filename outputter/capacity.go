@@ -0,0 +1,68 @@
+package outputter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/unifralabs/unifra-benchmark-tool/constants"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// SaveCapacityResults writes the capacity search results, with every probe, to the output directory
+func SaveCapacityResults(
+	outputDir string,
+	nodes tooltypes.Nodes,
+	parameters tooltypes.CapacitySearchParameters,
+	results map[string]tooltypes.CapacityResult,
+	tRunStart int64,
+	tRunEnd int64,
+) (tooltypes.CapacityResultsPayload, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return tooltypes.CapacityResultsPayload{}, err
+	}
+
+	payload := tooltypes.CapacityResultsPayload{
+		CLIArgs:    os.Args,
+		TRunStart:  tRunStart,
+		TRunEnd:    tRunEnd,
+		Nodes:      nodes,
+		Parameters: parameters,
+		Results:    results,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return tooltypes.CapacityResultsPayload{}, err
+	}
+
+	path := filepath.Join(outputDir, constants.CAPACITY_OUTPUT_FILE)
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		return tooltypes.CapacityResultsPayload{}, err
+	}
+
+	return payload, nil
+}
+
+// PrintCapacityResults prints the capacity of each node
+func PrintCapacityResults(results map[string]tooltypes.CapacityResult) {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		result := results[name]
+		rows = append(rows, []string{
+			name,
+			fmt.Sprintf("%d", result.Capacity),
+			fmt.Sprintf("%d", len(result.Probes)),
+		})
+	}
+	utils.PrintTable(rows, []string{"node", "max sustainable rps", "probes"})
+}
@@ -0,0 +1,211 @@
+package outputter
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/unifralabs/unifra-benchmark-tool/constants"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/font/liberation"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// DefaultComparisonPlotMetrics are the metrics plotted by PlotComparison when none are given
+var DefaultComparisonPlotMetrics = []string{"success", "throughput", "p50", "p99"}
+
+// LoadComparisonResults loads the results of one or more results files for a
+// comparison. Results are named after their node, or after their file and
+// node when node names alone are ambiguous. The names are returned in file
+// order, then node order, the first one being the comparison baseline.
+func LoadComparisonResults(paths []string) (map[string]tooltypes.LoadTestOutput, []string, tooltypes.Nodes, error) {
+	payloads := make([]tooltypes.SingleRunResultsPayload, len(paths))
+	counts := make(map[string]int)
+	for i, path := range paths {
+		payload, err := LoadSingleRunResults(path)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		payloads[i] = payload
+		for name := range payload.Results {
+			counts[name]++
+		}
+	}
+
+	labels := fileLabels(paths)
+	results := make(map[string]tooltypes.LoadTestOutput)
+	names := []string{}
+	nodes := make(tooltypes.Nodes)
+	for i, payload := range payloads {
+		nodeNames := make([]string, 0, len(payload.Results))
+		for name := range payload.Results {
+			nodeNames = append(nodeNames, name)
+		}
+		sort.Strings(nodeNames)
+
+		for _, nodeName := range nodeNames {
+			name := nodeName
+			if counts[nodeName] > 1 {
+				name = labels[i] + "/" + nodeName
+			}
+			if _, exists := results[name]; exists {
+				return nil, nil, nil, fmt.Errorf("results %s appear twice", name)
+			}
+
+			results[name] = payload.Results[nodeName]
+			names = append(names, name)
+			node := payload.Nodes[nodeName]
+			node.Name = name
+			nodes[name] = node
+		}
+	}
+
+	if len(names) < 2 {
+		return nil, nil, nil, fmt.Errorf("a comparison requires at least two results, found %d", len(names))
+	}
+	return results, names, nodes, nil
+}
+
+// fileLabels names the results files of a comparison after their base name
+// without extension, or after their path without extension when base names
+// collide, such as the results files of runs saved in different directories
+func fileLabels(paths []string) []string {
+	labels := make([]string, len(paths))
+	counts := make(map[string]int)
+	for i, path := range paths {
+		labels[i] = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		counts[labels[i]]++
+	}
+	for i, path := range paths {
+		if counts[labels[i]] > 1 {
+			labels[i] = strings.TrimSuffix(filepath.Clean(path), filepath.Ext(path))
+		}
+	}
+	return labels
+}
+
+// PlotComparison plots, for each metric, the percent delta of every result
+// against the first one (the baseline) by target rate
+func PlotComparison(
+	results map[string]tooltypes.LoadTestOutput,
+	names []string,
+	metrics []string,
+	colors map[string]string,
+	testName string,
+	outputDir string,
+) error {
+	if len(metrics) == 0 {
+		metrics = DefaultComparisonPlotMetrics
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	font.DefaultCache.Add(liberation.Collection())
+	face := font.DefaultCache.Lookup(font.Font{Typeface: "Liberation", Variant: "Mono"}, 12)
+
+	plotColors := constants.GetPlotColors()
+	baseline := names[0]
+
+	for _, metric := range metrics {
+		baseValues, err := metricByRate(results[baseline], metric)
+		if err != nil {
+			return err
+		}
+
+		p := plot.New()
+		setPlotFont(p, face.Font)
+		setPlotFontSize(p)
+
+		for _, name := range names[1:] {
+			values, err := metricByRate(results[name], metric)
+			if err != nil {
+				return err
+			}
+
+			pts := plotter.XYs{}
+			for _, rate := range sortedRates(values) {
+				base, ok := baseValues[rate]
+				if !ok || base == 0 || math.IsNaN(base) || math.IsNaN(values[rate]) {
+					continue
+				}
+				pts = append(pts, plotter.XY{X: float64(rate), Y: (values[rate] - base) / base * 100})
+			}
+			if len(pts) == 0 {
+				continue
+			}
+
+			line, points, err := plotter.NewLinePoints(pts)
+			if err != nil {
+				return err
+			}
+			resultColors, err := determineColors(colors[name], []string{metric}, plotColors)
+			if err != nil {
+				return err
+			}
+			line.Color = resultColors[0]
+			points.Color = resultColors[0]
+			points.Shape = draw.CircleGlyph{}
+			points.Radius = vg.Points(5)
+
+			p.Add(line, points)
+			p.Legend.Add(name, line, points)
+		}
+
+		// Zero line of the baseline
+		p.Add(plotter.NewFunction(func(float64) float64 { return 0 }))
+
+		p.Title.Text = fmt.Sprintf("%s vs %s\n(percent delta by request rate)", metric, baseline)
+		p.X.Label.Text = "requests per second"
+		if testName != "" {
+			p.X.Label.Text += "\n[" + testName + "]"
+		}
+		p.Y.Label.Text = metric + " delta (%)"
+		p.Legend.Top = true
+		p.Legend.Left = true
+		AddTickGrid(p)
+
+		path := filepath.Join(outputDir, "diff_"+metric+".png")
+		if err := savePlot(p, path); err != nil {
+			return fmt.Errorf("failed to save %s comparison plot: %w", metric, err)
+		}
+	}
+
+	return nil
+}
+
+// metricByRate returns the values of a metric by target rate, keeping the
+// first attack of each rate
+func metricByRate(result tooltypes.LoadTestOutput, metric string) (map[int]float64, error) {
+	values, err := LoadTestMetric(result, metric)
+	if err != nil {
+		return nil, err
+	}
+
+	byRate := make(map[int]float64, len(values))
+	for i, value := range values {
+		if i >= len(result.TargetRate) {
+			break
+		}
+		if _, exists := byRate[result.TargetRate[i]]; !exists {
+			byRate[result.TargetRate[i]] = value
+		}
+	}
+	return byRate, nil
+}
+
+func sortedRates(values map[int]float64) []int {
+	rates := make([]int, 0, len(values))
+	for rate := range values {
+		rates = append(rates, rate)
+	}
+	sort.Ints(rates)
+	return rates
+}
@@ -0,0 +1,117 @@
+package outputter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// PrintRuns prints one line per run of the history
+func PrintRuns(runs []tooltypes.RunRecord) {
+	rows := make([][]string, 0, len(runs))
+	for _, run := range runs {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", run.ID),
+			string(run.Kind),
+			run.TestName,
+			formatRunTime(run.TRunStart),
+			fmt.Sprintf("%d", run.TRunEnd-run.TRunStart),
+			strings.Join(sortedNodeNames(run.Nodes), ", "),
+		})
+	}
+	utils.PrintTable(rows, []string{"run", "kind", "test", "start", "duration [s]", "nodes"})
+}
+
+// PrintRun prints a run of the history with its results
+func PrintRun(run *tooltypes.RunRecord) {
+	utils.PrintHeader(fmt.Sprintf("Run %d: %s %s", run.ID, run.Kind, run.TestName))
+	utils.PrintBullet(fmt.Sprintf("start: %s", formatRunTime(run.TRunStart)))
+	utils.PrintBullet(fmt.Sprintf("end: %s", formatRunTime(run.TRunEnd)))
+	utils.PrintBullet(fmt.Sprintf("command: %s", strings.Join(run.CLIArgs, " ")))
+	utils.PrintBullet(fmt.Sprintf("parameters: %s", run.Parameters))
+	fmt.Println()
+	nodeRows := make([][]string, 0, len(run.Nodes))
+	for _, name := range sortedNodeNames(run.Nodes) {
+		node := run.Nodes[name]
+		network := "-"
+		if node.Network != nil {
+			network = fmt.Sprintf("%v", node.Network)
+		}
+		nodeRows = append(nodeRows, []string{name, node.URL, node.ClientVersion, network})
+	}
+	utils.PrintTable(nodeRows, []string{"node", "url", "client version", "network"})
+
+	if len(run.Attacks) > 0 {
+		fmt.Println()
+		rows := make([][]string, 0, len(run.Attacks))
+		for _, m := range run.Attacks {
+			rows = append(rows, []string{
+				m.Node,
+				fmt.Sprintf("%d", m.Attack),
+				fmt.Sprintf("%d", m.TargetRate),
+				formatOptionalFloat(m.Throughput, "%.1f", 1),
+				formatOptionalFloat(m.Success, "%.2f%%", 100),
+				formatOptionalFloat(m.P50, "%.1f", 1000),
+				formatOptionalFloat(m.P99, "%.1f", 1000),
+			})
+		}
+		utils.PrintTable(rows, []string{"node", "attack", "rate", "throughput", "success", "p50 [ms]", "p99 [ms]"})
+	}
+
+	if run.Tx != nil {
+		fmt.Println()
+		rows := make([][]string, 0, len(run.Tx.Blocks))
+		for _, b := range run.Tx.Blocks {
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", b.BlockNum),
+				fmt.Sprintf("%d", b.NumTxs),
+				fmt.Sprintf("%d", b.GasUsed),
+				fmt.Sprintf("%.2f%%", b.GasUtilization),
+				fmt.Sprintf("%d", b.BlockTime),
+			})
+		}
+		utils.PrintTable(rows, []string{"block", "transactions", "gas used", "utilization", "block time [s]"})
+		utils.PrintBullet(fmt.Sprintf("%s: %d TPS, %d mined transactions", run.Tx.TxType, int(run.Tx.TPS), run.Tx.MinedTxs))
+	}
+}
+
+// ExportRun writes a run of the history as JSON to a file, or to stdout if path is empty
+func ExportRun(run *tooltypes.RunRecord, path string) error {
+	jsonData, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	jsonData = append(jsonData, '\n')
+
+	if path == "" {
+		_, err = os.Stdout.Write(jsonData)
+		return err
+	}
+	return os.WriteFile(path, jsonData, 0644)
+}
+
+func formatRunTime(t int64) string {
+	return time.Unix(t, 0).Format("2006-01-02 15:04:05")
+}
+
+func formatOptionalFloat(value *float64, format string, scale float64) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf(format, *value*scale)
+}
+
+func sortedNodeNames(nodes tooltypes.Nodes) []string {
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
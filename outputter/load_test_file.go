@@ -0,0 +1,151 @@
+package outputter
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// savedLoadTestVersion is the version of the SavedLoadTest file format
+const savedLoadTestVersion = 1
+
+// SaveLoadTest writes a generated load test, with its calls and random seed,
+// so that it can be replayed later. Paths ending in .gz are gzip-compressed.
+func SaveLoadTest(path string, test tooltypes.LoadTest) error {
+	saved := tooltypes.SavedLoadTest{
+		Version:        savedLoadTestVersion,
+		TestParameters: test.TestParameters,
+		Calls:          [][]*tooltypes.JsonrpcMessage{},
+		Attacks:        make([]tooltypes.SavedVegetaAttack, len(test.Attacks)),
+	}
+
+	for i, attack := range test.Attacks {
+		index := -1
+		for j, calls := range saved.Calls {
+			if sameCalls(calls, attack.Calls) {
+				index = j
+				break
+			}
+		}
+		if index < 0 {
+			index = len(saved.Calls)
+			saved.Calls = append(saved.Calls, attack.Calls)
+		}
+
+		saved.Attacks[i] = tooltypes.SavedVegetaAttack{
+			Rate:       attack.Rate,
+			Duration:   attack.Duration,
+			Calls:      index,
+			VegetaArgs: attack.VegetaArgs,
+		}
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	if err := writeLoadTest(path, saved); err != nil {
+		return fmt.Errorf("failed to write load test %s: %w", path, err)
+	}
+
+	log.Info().Msgf("✅ Load test saved to %s", path)
+	return nil
+}
+
+// writeLoadTest encodes the load test to path. The gzip writer and the file
+// are closed explicitly, as the gzip footer and the final flush can fail too.
+func writeLoadTest(path string, saved tooltypes.SavedLoadTest) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	var gz *gzip.Writer
+	var w io.Writer = f
+	if strings.HasSuffix(path, ".gz") {
+		gz = gzip.NewWriter(f)
+		w = gz
+	}
+
+	err = json.NewEncoder(w).Encode(saved)
+	if gz != nil {
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// LoadLoadTest reads a load test written by SaveLoadTest. The calls are
+// decoded so that they marshal back to the same request bodies.
+func LoadLoadTest(path string) (tooltypes.LoadTest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return tooltypes.LoadTest{}, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return tooltypes.LoadTest{}, fmt.Errorf("failed to read load test %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	// Keep numbers as written instead of converting them to float64
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var saved tooltypes.SavedLoadTest
+	if err := dec.Decode(&saved); err != nil {
+		return tooltypes.LoadTest{}, fmt.Errorf("failed to parse load test %s: %w", path, err)
+	}
+	if saved.Version != savedLoadTestVersion {
+		return tooltypes.LoadTest{}, fmt.Errorf("unsupported load test version %d in %s", saved.Version, path)
+	}
+
+	test := tooltypes.LoadTest{
+		TestParameters: saved.TestParameters,
+		Attacks:        make([]tooltypes.VegetaAttack, len(saved.Attacks)),
+	}
+	for i, attack := range saved.Attacks {
+		if attack.Calls < 0 || attack.Calls >= len(saved.Calls) {
+			return tooltypes.LoadTest{}, fmt.Errorf("attack %d of %s refers to unknown calls %d", i, path, attack.Calls)
+		}
+		test.Attacks[i] = tooltypes.VegetaAttack{
+			Rate:       attack.Rate,
+			Duration:   attack.Duration,
+			Calls:      saved.Calls[attack.Calls],
+			VegetaArgs: attack.VegetaArgs,
+		}
+	}
+	return test, nil
+}
+
+// sameCalls reports whether two attacks share the same call list
+func sameCalls(a, b []*tooltypes.JsonrpcMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
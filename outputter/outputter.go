@@ -9,14 +9,17 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/unifralabs/unifra-benchmark-tool/constants"
 	"github.com/unifralabs/unifra-benchmark-tool/stats"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
 )
 
 type outputFormat struct {
-	AverageTPS float64           `json:"averageTPS"`
-	Blocks     []stats.BlockInfo `json:"blocks"`
+	AverageTPS  float64                `json:"averageTPS"`
+	Blocks      []stats.BlockInfo      `json:"blocks"`
+	Delegations *stats.DelegationStats `json:"delegations,omitempty"`
 }
 
-func OutputData(data *stats.CollectorData, outputDir string) error {
+// OutputData saves the results of a transaction benchmark to the results file of its type
+func OutputData(data *stats.CollectorData, outputDir string, txType tooltypes.TxType) error {
 	log.Info().Msg("💾 Saving run results initialized 💾")
 
 	if !isDir(outputDir) {
@@ -34,8 +37,9 @@ func OutputData(data *stats.CollectorData, outputDir string) error {
 	}
 
 	output := outputFormat{
-		AverageTPS: data.TPS,
-		Blocks:     blocks,
+		AverageTPS:  data.TPS,
+		Blocks:      blocks,
+		Delegations: data.Delegations,
 	}
 
 	jsonData, err := json.Marshal(output)
@@ -43,7 +47,7 @@ func OutputData(data *stats.CollectorData, outputDir string) error {
 		return fmt.Errorf("unable to marshal output data: %v", err)
 	}
 
-	path := filepath.Join(outputDir, constants.EOA_OUTPUT_FILE)
+	path := filepath.Join(outputDir, constants.TxOutputFile(string(txType)))
 	err = os.WriteFile(path, jsonData, 0644)
 	if err != nil {
 		return fmt.Errorf("unable to write output to file: %v", err)
@@ -164,7 +164,7 @@ func PlotLoadTestSuccess(
 		PlotOptions{
 			Colors:   colors,
 			TestName: testName,
-			Title:    "Success Rate vs Request Rate\n(higher is better)",
+			Title:    "Success Rate " + versusLabel(results) + "\n(higher is better)",
 			YLabel:   "success rate",
 			YLim:     []float64{-0.03, 1.03},
 		},
@@ -190,7 +190,7 @@ func PlotLoadTestThroughput(
 		PlotOptions{
 			Colors:   colors,
 			TestName: testName,
-			Title:    "Throughput vs Request Rate\n(higher is better)",
+			Title:    "Throughput " + versusLabel(results) + "\n(higher is better)",
 			YLabel:   "throughput\n(responses per second)",
 			YMin:     &zero,
 		},
@@ -227,7 +227,7 @@ func PlotLoadTestLatencies(
 			Colors:    colors,
 			TestName:  testName,
 			YMin:      ymin,
-			Title:     "Latency vs Request Rate\n(lower is better)",
+			Title:     "Latency " + versusLabel(results) + "\n(lower is better)",
 			YLabel:    "latency (seconds)",
 			YScaleLog: yscaleLog,
 		},
@@ -271,6 +271,8 @@ func PlotLoadTestResultMetrics(
 		}
 	}
 
+	overTime := plotsOverTime(results)
+
 	for name, result := range results {
 		resultColors, err := determineColors(options.Colors[name], metrics, plotColors)
 		if err != nil {
@@ -283,9 +285,14 @@ func PlotLoadTestResultMetrics(
 				label += " " + metric
 			}
 
+			elapsed := 0
 			pts := make(plotter.XYs, len(result.TargetRate))
 			for i := range result.TargetRate {
 				pts[i].X = float64(result.TargetRate[i])
+				if overTime {
+					elapsed += result.TargetDuration[i]
+					pts[i].X = float64(elapsed)
+				}
 				var y float64
 				switch metric {
 				case "success":
@@ -321,6 +328,9 @@ func PlotLoadTestResultMetrics(
 	// Set labels and options
 	p.Title.Text = options.Title
 	p.X.Label.Text = "requests per second"
+	if overTime {
+		p.X.Label.Text = "elapsed time (seconds)"
+	}
 	if options.TestName != nil {
 		p.X.Label.Text += "\n[" + *options.TestName + "]"
 	}
@@ -344,6 +354,27 @@ func PlotLoadTestResultMetrics(
 	return nil
 }
 
+// plotsOverTime reports whether results are plotted against the elapsed time
+// rather than the request rate, which is the case when the rates of the
+// attacks do not strictly increase (e.g. spike and soak tests)
+func plotsOverTime(results map[string]tooltypes.LoadTestOutput) bool {
+	for _, result := range results {
+		for i := 1; i < len(result.TargetRate); i++ {
+			if result.TargetRate[i] <= result.TargetRate[i-1] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func versusLabel(results map[string]tooltypes.LoadTestOutput) string {
+	if plotsOverTime(results) {
+		return "over Time"
+	}
+	return "vs Request Rate"
+}
+
 func determineColors(c string, metrics []string, plotColors map[string][]string) ([]color.Color, error) {
 
 	if colors, ok := plotColors[c]; ok {
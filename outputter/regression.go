@@ -0,0 +1,393 @@
+package outputter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/unifralabs/unifra-benchmark-tool/stats"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// DefaultRpcTolerances are the tolerances of the regression gate of RPC runs when none are given
+const DefaultRpcTolerances = "success=-1%,throughput=-5%,p50=+10%,p99=+10%"
+
+// DefaultTxTolerances are the tolerances of the regression gate of tx runs when none are given
+const DefaultTxTolerances = "tps=-5%,gas_rate=-5%"
+
+// TxRegressionMetrics are the metrics of transaction benchmarks that the regression gate checks
+var TxRegressionMetrics = []string{"tps", "utilization", "block_time", "gas_rate"}
+
+// RegressionResults are the results of a run checked by the regression gate:
+// the load test outputs of the nodes of an RPC run, or the results of the
+// transaction benchmarks of a tx run
+type RegressionResults struct {
+	Kind tooltypes.RunKind
+	Rpc  map[string]tooltypes.LoadTestOutput
+	Tx   map[tooltypes.TxType]tooltypes.TxRunResults
+}
+
+// LoadRegressionResults loads the RPC results file or the transaction
+// results file of a run
+func LoadRegressionResults(path string) (*RegressionResults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse results %s: %w", path, err)
+	}
+
+	switch {
+	case fields["averageTPS"] != nil:
+		var output outputFormat
+		if err := json.Unmarshal(data, &output); err != nil {
+			return nil, fmt.Errorf("failed to parse results %s: %w", path, err)
+		}
+		// Transaction results files are named after their tx type
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		txType := tooltypes.TxType(strings.ToUpper(strings.TrimSuffix(name, "_results")))
+		return &RegressionResults{
+			Kind: tooltypes.TxRun,
+			Tx:   map[tooltypes.TxType]tooltypes.TxRunResults{txType: txResultsFromOutput(txType, output)},
+		}, nil
+	case fields["results"] != nil && fields["parameters"] != nil:
+		return nil, fmt.Errorf("%s holds capacity search results, which the regression gate does not check", path)
+	case fields["results"] != nil:
+		payload, err := LoadSingleRunResults(path)
+		if err != nil {
+			return nil, err
+		}
+		return &RegressionResults{Kind: tooltypes.RpcRun, Rpc: payload.Results}, nil
+	default:
+		return nil, fmt.Errorf("%s is neither an RPC nor a transaction results file", path)
+	}
+}
+
+func txResultsFromOutput(txType tooltypes.TxType, output outputFormat) tooltypes.TxRunResults {
+	results := tooltypes.TxRunResults{
+		TxType: txType,
+		TPS:    output.AverageTPS,
+		Blocks: make([]tooltypes.TxBlock, 0, len(output.Blocks)),
+	}
+	for _, block := range output.Blocks {
+		results.MinedTxs += block.NumTxs
+		results.Blocks = append(results.Blocks, tooltypes.TxBlock{
+			BlockNum:       block.BlockNum,
+			CreatedAt:      block.CreatedAt,
+			NumTxs:         block.NumTxs,
+			GasUsed:        block.GasUsed,
+			GasLimit:       block.GasLimit,
+			GasUtilization: block.GasUtilization,
+			BlockTime:      block.BlockTime,
+			NumBlobs:       block.NumBlobs,
+			BlobGasUsed:    block.BlobGasUsed,
+			ExcessBlobGas:  block.ExcessBlobGas,
+		})
+	}
+	return results
+}
+
+// RegressionResultsFromRun returns the results of a run of the history
+func RegressionResultsFromRun(run *tooltypes.RunRecord) (*RegressionResults, error) {
+	switch run.Kind {
+	case tooltypes.RpcRun:
+		outputs := make(map[string]tooltypes.LoadTestOutput)
+		for _, attack := range run.Attacks {
+			output := outputs[attack.Node]
+			output.TargetRate = append(output.TargetRate, attack.TargetRate)
+			output.TargetDuration = append(output.TargetDuration, attack.TargetDuration)
+			output.Requests = append(output.Requests, attack.Requests)
+			output.ActualRate = append(output.ActualRate, attack.ActualRate)
+			output.Throughput = append(output.Throughput, attack.Throughput)
+			output.Success = append(output.Success, attack.Success)
+			output.Min = append(output.Min, attack.Min)
+			output.Mean = append(output.Mean, attack.Mean)
+			output.P50 = append(output.P50, attack.P50)
+			output.P90 = append(output.P90, attack.P90)
+			output.P95 = append(output.P95, attack.P95)
+			output.P99 = append(output.P99, attack.P99)
+			output.Max = append(output.Max, attack.Max)
+			outputs[attack.Node] = output
+		}
+		return &RegressionResults{Kind: tooltypes.RpcRun, Rpc: outputs}, nil
+	case tooltypes.TxRun:
+		if run.Tx == nil {
+			return nil, fmt.Errorf("run %d has no transaction results", run.ID)
+		}
+		return &RegressionResults{
+			Kind: tooltypes.TxRun,
+			Tx:   map[tooltypes.TxType]tooltypes.TxRunResults{run.Tx.TxType: *run.Tx},
+		}, nil
+	default:
+		return nil, fmt.Errorf("run %d is a %s run, which the regression gate does not check", run.ID, run.Kind)
+	}
+}
+
+// CheckRegressions compares every metric with a tolerance of the current
+// results to the baseline. RPC results are compared node by node and rate by
+// rate, or attack by attack when a run repeats a rate, and transaction
+// results tx type by tx type. A lone node is compared to the lone baseline
+// node whatever its name, while tx results must be of the baseline tx
+// types. Results of the baseline missing from the current run are regressions.
+func CheckRegressions(baseline, current *RegressionResults, tolerances []tooltypes.Tolerance) ([]tooltypes.RegressionCheck, error) {
+	if baseline.Kind != current.Kind {
+		return nil, fmt.Errorf("cannot compare %s results to a %s baseline", current.Kind, baseline.Kind)
+	}
+
+	if baseline.Kind == tooltypes.TxRun {
+		return checkTxRegressions(baseline.Tx, current.Tx, tolerances)
+	}
+	return checkRpcRegressions(baseline.Rpc, current.Rpc, tolerances)
+}
+
+// pairResults returns the sorted names of the baseline results, and the
+// names of the current results they compare to: the same names, or the lone
+// current node when both sides hold a single node, whatever its name
+func pairResults(baseline, current map[string]tooltypes.LoadTestOutput) ([]string, []string) {
+	baseNames := make([]string, 0, len(baseline))
+	for name := range baseline {
+		baseNames = append(baseNames, name)
+	}
+	slices.Sort(baseNames)
+
+	names := slices.Clone(baseNames)
+	if len(baseline) == 1 && len(current) == 1 {
+		for name := range current {
+			names[0] = name
+		}
+	}
+	return baseNames, names
+}
+
+func checkRpcRegressions(baseline, current map[string]tooltypes.LoadTestOutput, tolerances []tooltypes.Tolerance) ([]tooltypes.RegressionCheck, error) {
+	baseNames, names := pairResults(baseline, current)
+
+	checks := []tooltypes.RegressionCheck{}
+	for i, baseName := range baseNames {
+		name := names[i]
+		output, found := current[name]
+		// Runs repeating a rate, such as spike and soak runs, compare attack by attack
+		byAttack := repeatsRate(baseline[baseName]) || (found && repeatsRate(output))
+
+		for _, tolerance := range tolerances {
+			baseKeys, baseValues, err := metricByAttack(baseline[baseName], tolerance.Metric, byAttack)
+			if err != nil {
+				return nil, err
+			}
+			values := map[string]float64{}
+			if found {
+				if _, values, err = metricByAttack(output, tolerance.Metric, byAttack); err != nil {
+					return nil, err
+				}
+			}
+
+			for _, key := range baseKeys {
+				value, ok := values[key]
+				if !ok {
+					value = math.NaN()
+				}
+				if check, ok := checkMetric(name+" "+key, tolerance, baseValues[key], value); ok {
+					checks = append(checks, check)
+				}
+			}
+		}
+	}
+	return checks, nil
+}
+
+// repeatsRate tells whether several attacks of a result share a target rate
+func repeatsRate(result tooltypes.LoadTestOutput) bool {
+	seen := make(map[int]bool, len(result.TargetRate))
+	for _, rate := range result.TargetRate {
+		if seen[rate] {
+			return true
+		}
+		seen[rate] = true
+	}
+	return false
+}
+
+// metricByAttack returns the values of a metric keyed by the attacks they
+// come from, with the keys in order: "@ R rps" by increasing rate, or
+// "attack N @ R rps" in attack order when byAttack is set
+func metricByAttack(result tooltypes.LoadTestOutput, metric string, byAttack bool) ([]string, map[string]float64, error) {
+	if !byAttack {
+		byRate, err := metricByRate(result, metric)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys := []string{}
+		values := make(map[string]float64, len(byRate))
+		for _, rate := range sortedRates(byRate) {
+			key := fmt.Sprintf("@ %d rps", rate)
+			keys = append(keys, key)
+			values[key] = byRate[rate]
+		}
+		return keys, values, nil
+	}
+
+	metricValues, err := LoadTestMetric(result, metric)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := []string{}
+	values := make(map[string]float64, len(metricValues))
+	for i, value := range metricValues {
+		if i >= len(result.TargetRate) {
+			break
+		}
+		key := fmt.Sprintf("attack %d @ %d rps", i+1, result.TargetRate[i])
+		keys = append(keys, key)
+		values[key] = value
+	}
+	return keys, values, nil
+}
+
+func checkTxRegressions(baseline, current map[tooltypes.TxType]tooltypes.TxRunResults, tolerances []tooltypes.Tolerance) ([]tooltypes.RegressionCheck, error) {
+	for _, tolerance := range tolerances {
+		if !slices.Contains(TxRegressionMetrics, tolerance.Metric) {
+			return nil, fmt.Errorf("unknown metric: %s, expected one of %s", tolerance.Metric, strings.Join(TxRegressionMetrics, ", "))
+		}
+	}
+
+	// Each workload only compares to itself
+	baseTypes := sortedTxTypes(baseline)
+	txTypes := sortedTxTypes(current)
+	if !slices.Equal(baseTypes, txTypes) {
+		return nil, fmt.Errorf("cannot compare %s tx results to the %s baseline", joinTxTypes(txTypes), joinTxTypes(baseTypes))
+	}
+
+	checks := []tooltypes.RegressionCheck{}
+	for _, txType := range baseTypes {
+		for _, tolerance := range tolerances {
+			value := txMetric(current[txType], tolerance.Metric)
+			if check, ok := checkMetric(string(txType), tolerance, txMetric(baseline[txType], tolerance.Metric), value); ok {
+				checks = append(checks, check)
+			}
+		}
+	}
+	return checks, nil
+}
+
+func sortedTxTypes(results map[tooltypes.TxType]tooltypes.TxRunResults) []tooltypes.TxType {
+	txTypes := make([]tooltypes.TxType, 0, len(results))
+	for txType := range results {
+		txTypes = append(txTypes, txType)
+	}
+	slices.Sort(txTypes)
+	return txTypes
+}
+
+func joinTxTypes(txTypes []tooltypes.TxType) string {
+	names := make([]string, len(txTypes))
+	for i, txType := range txTypes {
+		names[i] = string(txType)
+	}
+	return strings.Join(names, ",")
+}
+
+// txMetric returns a metric of the results of a transaction benchmark, NaN
+// when the benchmark has no block to compute it from
+func txMetric(results tooltypes.TxRunResults, metric string) float64 {
+	blocks := make(map[uint64]*stats.BlockInfo, len(results.Blocks))
+	for _, block := range results.Blocks {
+		blocks[block.BlockNum] = &stats.BlockInfo{
+			BlockNum:       block.BlockNum,
+			GasUsed:        block.GasUsed,
+			GasUtilization: block.GasUtilization,
+			BlockTime:      block.BlockTime,
+		}
+	}
+	summary := stats.SummarizeBlocks(blocks)
+
+	switch metric {
+	case "tps":
+		return results.TPS
+	case "utilization":
+		if summary.Blocks == 0 {
+			return math.NaN()
+		}
+		return summary.AvgUtilization
+	case "block_time":
+		if summary.Blocks == 0 {
+			return math.NaN()
+		}
+		return summary.AvgBlockTime
+	default: // gas_rate, in Mgas/s
+		if summary.BlockTime == 0 {
+			return math.NaN()
+		}
+		return float64(summary.GasUsed) / float64(summary.BlockTime) / 1e6
+	}
+}
+
+// checkMetric compares a value to its baseline. Metrics without a baseline
+// value are not checked, a missing current value is a regression.
+func checkMetric(subject string, tolerance tooltypes.Tolerance, baseline, current float64) (tooltypes.RegressionCheck, bool) {
+	if math.IsNaN(baseline) {
+		return tooltypes.RegressionCheck{}, false
+	}
+
+	check := tooltypes.RegressionCheck{
+		Subject:   subject,
+		Metric:    tolerance.Metric,
+		Baseline:  baseline,
+		Current:   current,
+		Tolerance: tolerance,
+	}
+	switch {
+	case math.IsNaN(current):
+		check.Delta = math.NaN()
+		check.Regressed = true
+	case current == baseline:
+		check.Delta = 0
+	default:
+		// A zero baseline gives an infinite delta, of the sign of the change
+		check.Delta = (current - baseline) / math.Abs(baseline) * 100
+		check.Regressed = !tolerance.Allows(check.Delta)
+	}
+	return check, true
+}
+
+// PrintRegressionChecks prints the verdict of every check of the regression gate
+func PrintRegressionChecks(checks []tooltypes.RegressionCheck) {
+	rows := make([][]string, 0, len(checks))
+	for _, check := range checks {
+		current, delta, verdict := "-", "-", "pass"
+		if !math.IsNaN(check.Current) {
+			current = fmt.Sprintf("%.4g", check.Current)
+			delta = fmt.Sprintf("%+.2f%%", check.Delta)
+		}
+		if check.Regressed {
+			verdict = "REGRESSION"
+			if math.IsNaN(check.Current) {
+				verdict = "MISSING"
+			}
+		}
+
+		sign := "+"
+		if check.Tolerance.Drop {
+			sign = "-"
+		}
+		rows = append(rows, []string{
+			check.Subject,
+			check.Metric,
+			fmt.Sprintf("%.4g", check.Baseline),
+			current,
+			delta,
+			fmt.Sprintf("%s%g%%", sign, check.Tolerance.Percent),
+			verdict,
+		})
+	}
+
+	utils.PrintHeader("Regression gate")
+	utils.PrintTable(rows, []string{"subject", "metric", "baseline", "current", "delta", "tolerance", "verdict"})
+}
@@ -0,0 +1,160 @@
+package outputter
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func TestCheckMetric(t *testing.T) {
+	p99 := tooltypes.Tolerance{Metric: "p99", Percent: 10}
+	throughput := tooltypes.Tolerance{Metric: "throughput", Percent: 5, Drop: true}
+
+	tests := []struct {
+		name          string
+		tolerance     tooltypes.Tolerance
+		baseline      float64
+		current       float64
+		wantChecked   bool
+		wantDelta     float64
+		wantRegressed bool
+	}{
+		{name: "NaN baseline", tolerance: p99, baseline: math.NaN(), current: 1, wantChecked: false},
+		{name: "NaN current", tolerance: p99, baseline: 1, current: math.NaN(), wantChecked: true, wantDelta: math.NaN(), wantRegressed: true},
+		{name: "zero baseline unchanged", tolerance: p99, baseline: 0, current: 0, wantChecked: true, wantDelta: 0},
+		{name: "zero baseline increase", tolerance: p99, baseline: 0, current: 1, wantChecked: true, wantDelta: math.Inf(1), wantRegressed: true},
+		{name: "zero baseline drop", tolerance: throughput, baseline: 0, current: -1, wantChecked: true, wantDelta: math.Inf(-1), wantRegressed: true},
+		{name: "within tolerance", tolerance: p99, baseline: 100, current: 105, wantChecked: true, wantDelta: 5},
+		{name: "beyond tolerance", tolerance: throughput, baseline: 100, current: 90, wantChecked: true, wantDelta: -10, wantRegressed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, ok := checkMetric("node @ 10 rps", tt.tolerance, tt.baseline, tt.current)
+			if ok != tt.wantChecked {
+				t.Fatalf("checkMetric checked = %t, want %t", ok, tt.wantChecked)
+			}
+			if !ok {
+				return
+			}
+			if check.Regressed != tt.wantRegressed {
+				t.Errorf("checkMetric regressed = %t, want %t", check.Regressed, tt.wantRegressed)
+			}
+			if math.IsNaN(tt.wantDelta) != math.IsNaN(check.Delta) || (!math.IsNaN(tt.wantDelta) && check.Delta != tt.wantDelta) {
+				t.Errorf("checkMetric delta = %g, want %g", check.Delta, tt.wantDelta)
+			}
+		})
+	}
+}
+
+func rpcResults(outputs map[string]tooltypes.LoadTestOutput) *RegressionResults {
+	return &RegressionResults{Kind: tooltypes.RpcRun, Rpc: outputs}
+}
+
+func throughputOutput(rates []int, values ...float64) tooltypes.LoadTestOutput {
+	output := tooltypes.LoadTestOutput{TargetRate: rates}
+	for _, value := range values {
+		output.Throughput = append(output.Throughput, float(value))
+	}
+	return output
+}
+
+func TestCheckRegressions(t *testing.T) {
+	tolerances := []tooltypes.Tolerance{{Metric: "throughput", Percent: 5, Drop: true}}
+
+	type verdict struct {
+		subject   string
+		regressed bool
+	}
+	tests := []struct {
+		name     string
+		baseline *RegressionResults
+		current  *RegressionResults
+		want     []verdict
+		wantErr  string
+	}{
+		{
+			name:     "by rate",
+			baseline: rpcResults(map[string]tooltypes.LoadTestOutput{"geth": throughputOutput([]int{50, 10}, 50, 10)}),
+			current:  rpcResults(map[string]tooltypes.LoadTestOutput{"geth": throughputOutput([]int{10, 50}, 10, 40)}),
+			want:     []verdict{{"geth @ 10 rps", false}, {"geth @ 50 rps", true}},
+		},
+		{
+			name:     "by attack when a rate repeats",
+			baseline: rpcResults(map[string]tooltypes.LoadTestOutput{"geth": throughputOutput([]int{10, 50, 10}, 10, 50, 10)}),
+			current:  rpcResults(map[string]tooltypes.LoadTestOutput{"geth": throughputOutput([]int{10, 50, 10}, 10, 50, 5)}),
+			want:     []verdict{{"geth attack 1 @ 10 rps", false}, {"geth attack 2 @ 50 rps", false}, {"geth attack 3 @ 10 rps", true}},
+		},
+		{
+			name:     "lone nodes of different names",
+			baseline: rpcResults(map[string]tooltypes.LoadTestOutput{"geth": throughputOutput([]int{10}, 10)}),
+			current:  rpcResults(map[string]tooltypes.LoadTestOutput{"reth": throughputOutput([]int{10}, 10)}),
+			want:     []verdict{{"reth @ 10 rps", false}},
+		},
+		{
+			name: "baseline node missing",
+			baseline: rpcResults(map[string]tooltypes.LoadTestOutput{
+				"geth": throughputOutput([]int{10}, 10),
+				"reth": throughputOutput([]int{10}, 10),
+			}),
+			current: rpcResults(map[string]tooltypes.LoadTestOutput{"geth": throughputOutput([]int{10}, 10)}),
+			want:    []verdict{{"geth @ 10 rps", false}, {"reth @ 10 rps", true}},
+		},
+		{
+			name:     "baseline rate missing",
+			baseline: rpcResults(map[string]tooltypes.LoadTestOutput{"geth": throughputOutput([]int{10, 50}, 10, 50)}),
+			current:  rpcResults(map[string]tooltypes.LoadTestOutput{"geth": throughputOutput([]int{10}, 10)}),
+			want:     []verdict{{"geth @ 10 rps", false}, {"geth @ 50 rps", true}},
+		},
+		{
+			name:     "kinds differ",
+			baseline: rpcResults(map[string]tooltypes.LoadTestOutput{"geth": throughputOutput([]int{10}, 10)}),
+			current:  &RegressionResults{Kind: tooltypes.TxRun},
+			wantErr:  "cannot compare tx results to a rpc baseline",
+		},
+		{
+			name:     "tx types differ",
+			baseline: &RegressionResults{Kind: tooltypes.TxRun, Tx: map[tooltypes.TxType]tooltypes.TxRunResults{tooltypes.EOA: {TxType: tooltypes.EOA, TPS: 10}}},
+			current:  &RegressionResults{Kind: tooltypes.TxRun, Tx: map[tooltypes.TxType]tooltypes.TxRunResults{tooltypes.ERC20: {TxType: tooltypes.ERC20, TPS: 10}}},
+			wantErr:  "cannot compare ERC20 tx results to the EOA baseline",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txTolerances := tolerances
+			if tt.baseline.Kind == tooltypes.TxRun {
+				txTolerances = []tooltypes.Tolerance{{Metric: "tps", Percent: 5, Drop: true}}
+			}
+			checks, err := CheckRegressions(tt.baseline, tt.current, txTolerances)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CheckRegressions error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckRegressions error = %v", err)
+			}
+
+			got := make([]verdict, len(checks))
+			for i, check := range checks {
+				got[i] = verdict{check.Subject, check.Regressed}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("CheckRegressions = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("check %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package outputter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// DefaultReportMetrics are the metrics printed by reports when none are given
+var DefaultReportMetrics = []string{"success", "throughput", "p50", "p90", "p99"}
+
+// LoadSingleRunResults reads the results saved by SaveSingleRunResults
+func LoadSingleRunResults(path string) (tooltypes.SingleRunResultsPayload, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tooltypes.SingleRunResultsPayload{}, err
+	}
+
+	var payload tooltypes.SingleRunResultsPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return tooltypes.SingleRunResultsPayload{}, fmt.Errorf("failed to parse results %s: %w", path, err)
+	}
+	return payload, nil
+}
+
+// PrintLoadTestResults prints one table per metric, with a column per result
+// and a row per rate. names orders the columns, nil meaning sorted names. In
+// comparison mode, the results are compared against the first one.
+func PrintLoadTestResults(results map[string]tooltypes.LoadTestOutput, names []string, metrics []string, comparison bool) error {
+	if len(metrics) == 0 {
+		metrics = DefaultReportMetrics
+	}
+
+	tables := make(map[string]interface{}, len(results))
+	for name, result := range results {
+		table := map[string]interface{}{"target_rate": result.TargetRate}
+		for _, metric := range metrics {
+			values, err := LoadTestMetric(result, metric)
+			if err != nil {
+				return err
+			}
+			table[metric] = values
+		}
+		tables[name] = table
+	}
+
+	indent := 0
+	utils.PrintMetricTables(tables, names, metrics, "", nil, &comparison, &indent)
+	return nil
+}
+
+// LoadTestMetric returns the values of a metric of a load test output, NaN
+// standing for missing values
+func LoadTestMetric(result tooltypes.LoadTestOutput, metric string) ([]float64, error) {
+	var column []*float64
+	switch metric {
+	case "success":
+		column = result.Success
+	case "throughput":
+		column = result.Throughput
+	case "actual_rate":
+		column = result.ActualRate
+	case "min":
+		column = result.Min
+	case "mean":
+		column = result.Mean
+	case "p50":
+		column = result.P50
+	case "p90":
+		column = result.P90
+	case "p95":
+		column = result.P95
+	case "p99":
+		column = result.P99
+	case "max":
+		column = result.Max
+	default:
+		return nil, fmt.Errorf("unknown metric: %s", metric)
+	}
+
+	values := make([]float64, len(column))
+	for i, value := range column {
+		values[i] = math.NaN()
+		if value != nil {
+			values[i] = *value
+		}
+	}
+	return values, nil
+}
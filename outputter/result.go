@@ -15,6 +15,7 @@ func SaveSingleRunResults(
 	outputDir string,
 	nodes tooltypes.Nodes,
 	results map[string]tooltypes.LoadTestOutput,
+	repeats map[string][]tooltypes.LoadTestOutput,
 	figures bool,
 	testName string,
 	tRunStart int64,
@@ -37,6 +38,7 @@ func SaveSingleRunResults(
 		TRunEnd:   tRunEnd,
 		Nodes:     nodes,
 		Results:   results,
+		Repeats:   repeats,
 	}
 
 	jsonData, err := json.Marshal(payload)
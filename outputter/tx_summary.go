@@ -0,0 +1,36 @@
+package outputter
+
+import (
+	"fmt"
+
+	"github.com/unifralabs/unifra-benchmark-tool/stats"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// PrintTxSummary compares the TPS and gas utilization of the transaction
+// benchmarks of a run, in the order they ran. Failed benchmarks have no results.
+func PrintTxSummary(txTypes []tooltypes.TxType, results map[tooltypes.TxType]*stats.CollectorData) {
+	rows := make([][]string, 0, len(txTypes))
+	for _, txType := range txTypes {
+		data, ok := results[txType]
+		if !ok {
+			rows = append(rows, []string{string(txType), "failed", "-", "-", "-", "-", "-"})
+			continue
+		}
+
+		summary := stats.SummarizeBlocks(data.BlockInfo)
+		rows = append(rows, []string{
+			string(txType),
+			fmt.Sprintf("%d", data.MinedTxs),
+			fmt.Sprintf("%d", int(data.TPS)),
+			fmt.Sprintf("%d", summary.Blocks),
+			fmt.Sprintf("%.2f%%", summary.AvgUtilization),
+			fmt.Sprintf("%.2f", summary.AvgBlockTime),
+			summary.GasRate(),
+		})
+	}
+
+	utils.PrintHeader("Transaction benchmarks")
+	utils.PrintTable(rows, []string{"type", "mined txs", "TPS", "blocks", "avg utilization", "avg block time [s]", "gas rate [Mgas/s]"})
+}
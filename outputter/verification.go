@@ -0,0 +1,78 @@
+package outputter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"github.com/unifralabs/unifra-benchmark-tool/constants"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// printedMismatches is the number of mismatches printed per method
+const printedMismatches = 3
+
+// printedResponseLength truncates the responses of the printed mismatches
+const printedResponseLength = 512
+
+// SaveVerificationReport writes the response verification report, with its mismatches, to the output directory
+func SaveVerificationReport(outputDir string, report *tooltypes.VerificationReport) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(outputDir, constants.VERIFICATION_OUTPUT_FILE)
+	return os.WriteFile(path, jsonData, 0644)
+}
+
+// PrintVerificationReport prints the mismatches of each method, then a few of
+// them with their request and both responses
+func PrintVerificationReport(report *tooltypes.VerificationReport) {
+	utils.PrintHeader(fmt.Sprintf("Responses vs %s", report.Reference))
+
+	rows := make([][]string, 0, len(report.Methods))
+	for _, method := range report.Methods {
+		rows = append(rows, []string{
+			method.Method,
+			fmt.Sprintf("%d", method.Compared),
+			fmt.Sprintf("%d", method.Mismatched),
+			fmt.Sprintf("%d", method.Skipped),
+		})
+	}
+	utils.PrintTable(rows, []string{"method", "compared", "mismatched", "skipped"})
+
+	for _, method := range report.Methods {
+		for i, mismatch := range method.Mismatches {
+			if i == printedMismatches {
+				log.Warn().Msgf("%s: %d more mismatches", method.Method, method.Mismatched-printedMismatches)
+				break
+			}
+			request, _ := json.Marshal(mismatch.Request)
+			log.Warn().Msgf("%s mismatch on %s", mismatch.Node, method.Method)
+			log.Warn().Msgf("  request:   %s", request)
+			log.Warn().Msgf("  %s: %s", mismatch.Node, truncate(string(mismatch.Response), printedResponseLength))
+			log.Warn().Msgf("  %s: %s", report.Reference, truncate(string(mismatch.ReferenceResponse), printedResponseLength))
+		}
+	}
+
+	if mismatched := report.Mismatched(); mismatched > 0 {
+		log.Warn().Msgf("%d responses differ from %s", mismatched, report.Reference)
+	} else {
+		log.Info().Msgf("All compared responses match %s", report.Reference)
+	}
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return s[:length] + "..."
+}
@@ -1,12 +1,21 @@
 package rpc_builder
 
 import (
+	"fmt"
 	"slices"
 
 	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
 	"github.com/unifralabs/unifra-benchmark-tool/utils"
 )
 
+const (
+	defaultStartBlock int64 = 10_000_000
+	defaultEndBlock   int64 = 16_000_000
+)
+
+// GenerateBlockNumbers draws n block numbers from [startBlock, endBlock].
+// When the blocks available on the network are known, the range is clamped
+// to them so that every generated block can be served.
 func GenerateBlockNumbers(
 	n int,
 	startBlock int64,
@@ -15,6 +24,17 @@ func GenerateBlockNumbers(
 	randomSeed *tooltypes.RandomSeed,
 	network *string,
 ) ([]int64, error) {
+	if available, ok := NetworkBlockRange(network); ok {
+		r, err := clampBlockRange(tooltypes.BlockRange{Start: startBlock, End: endBlock}, available)
+		if err != nil {
+			return nil, err
+		}
+		startBlock, endBlock = r.Start, r.End
+	}
+	if endBlock < startBlock {
+		return nil, fmt.Errorf("invalid block range %d-%d", startBlock, endBlock)
+	}
+
 	// Seed a generator
 	rng, err := utils.GetRNG(randomSeed)
 	if err != nil {
@@ -22,21 +42,24 @@ func GenerateBlockNumbers(
 	}
 
 	// Generate blocks
-	allBlocks := make([]int64, endBlock-startBlock+1)
-	for i := range allBlocks {
-		allBlocks[i] = startBlock + int64(i)
-	}
-
+	nBlocks := endBlock - startBlock + 1
 	chosen := make([]int64, n)
-	if n > len(allBlocks) {
+	if int64(n) > nBlocks {
 		for i := range chosen {
-			chosen[i] = allBlocks[rng.Intn(len(allBlocks))]
+			chosen[i] = startBlock + rng.Int63n(nBlocks)
 		}
 	} else {
-		perm := rng.Perm(len(allBlocks))
-		for i := 0; i < n; i++ {
-			chosen[i] = allBlocks[perm[i]]
+		// Sample without replacement (Floyd's algorithm), without materializing the range
+		taken := make(map[int64]bool, n)
+		for i, j := 0, nBlocks-int64(n); j < nBlocks; i, j = i+1, j+1 {
+			t := rng.Int63n(j + 1)
+			if taken[t] {
+				t = j
+			}
+			taken[t] = true
+			chosen[i] = startBlock + t
 		}
+		rng.Shuffle(len(chosen), func(i, j int) { chosen[i], chosen[j] = chosen[j], chosen[i] })
 	}
 
 	// Sort if required
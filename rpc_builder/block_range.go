@@ -0,0 +1,218 @@
+package rpc_builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/unifralabs/unifra-benchmark-tool/samples"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+type BlockRangeMode string
+
+const (
+	// LastBlocksRange selects the last N blocks before the head
+	LastBlocksRange BlockRangeMode = "last"
+	// ArchiveRange selects N blocks deep from the head, or all blocks with state
+	ArchiveRange BlockRangeMode = "archive"
+	// RecentRange selects the blocks whose state is kept by pruned nodes
+	RecentRange BlockRangeMode = "recent"
+	// FixedRange selects an explicit start-end range
+	FixedRange BlockRangeMode = "fixed"
+)
+
+// recentStateBlocks is the number of recent block states kept by pruned nodes
+const recentStateBlocks int64 = 128
+
+// BlockRangeSpec describes which blocks historical tests target, relative to
+// what the node serves
+type BlockRangeSpec struct {
+	Mode   BlockRangeMode
+	Blocks int64
+	Start  int64
+	End    int64
+}
+
+// ParseBlockRangeSpec parses a block range specification: "last:N",
+// "archive", "archive:N", "recent" or "START-END"
+func ParseBlockRangeSpec(spec string) (BlockRangeSpec, error) {
+	spec = strings.TrimSpace(spec)
+	mode, value, _ := strings.Cut(spec, ":")
+
+	switch BlockRangeMode(mode) {
+	case LastBlocksRange, ArchiveRange:
+		s := BlockRangeSpec{Mode: BlockRangeMode(mode)}
+		if value == "" {
+			if s.Mode == LastBlocksRange {
+				return BlockRangeSpec{}, fmt.Errorf("block range %q requires a number of blocks", spec)
+			}
+			return s, nil
+		}
+		blocks, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || blocks <= 0 {
+			return BlockRangeSpec{}, fmt.Errorf("invalid number of blocks in block range %q", spec)
+		}
+		s.Blocks = blocks
+		return s, nil
+	case RecentRange:
+		return BlockRangeSpec{Mode: RecentRange}, nil
+	}
+
+	startStr, endStr, ok := strings.Cut(spec, "-")
+	if !ok {
+		return BlockRangeSpec{}, fmt.Errorf("unknown block range %q", spec)
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(startStr), 10, 64)
+	if err != nil {
+		return BlockRangeSpec{}, fmt.Errorf("invalid start block in block range %q", spec)
+	}
+	end, err := strconv.ParseInt(strings.TrimSpace(endStr), 10, 64)
+	if err != nil {
+		return BlockRangeSpec{}, fmt.Errorf("invalid end block in block range %q", spec)
+	}
+	if start < 0 || end < start {
+		return BlockRangeSpec{}, fmt.Errorf("invalid block range %q", spec)
+	}
+	return BlockRangeSpec{Mode: FixedRange, Start: start, End: end}, nil
+}
+
+// ResolveBlockRange turns a block range specification into concrete blocks
+// that the node described by info can serve
+func ResolveBlockRange(spec BlockRangeSpec, info tooltypes.ChainInfo) (tooltypes.BlockRange, error) {
+	head := int64(info.Head)
+	earliest := int64(info.EarliestState)
+
+	var r tooltypes.BlockRange
+	switch spec.Mode {
+	case LastBlocksRange:
+		r = tooltypes.BlockRange{Start: head - spec.Blocks + 1, End: head}
+	case ArchiveRange:
+		if !info.Archive {
+			log.Warn().Msgf("node is not an archive node, state is only available from block %d", earliest)
+		}
+		r = tooltypes.BlockRange{Start: earliest, End: head}
+		if spec.Blocks > 0 {
+			r.Start = head - spec.Blocks + 1
+		}
+	case RecentRange:
+		r = tooltypes.BlockRange{Start: head - recentStateBlocks + 1, End: head}
+	case FixedRange:
+		r = tooltypes.BlockRange{Start: spec.Start, End: spec.End}
+	default:
+		return tooltypes.BlockRange{}, fmt.Errorf("unknown block range mode: %s", spec.Mode)
+	}
+
+	return clampBlockRange(r, tooltypes.BlockRange{Start: earliest, End: head})
+}
+
+// IntersectBlockRanges returns the blocks served by all nodes
+func IntersectBlockRanges(ranges []tooltypes.BlockRange) (tooltypes.BlockRange, error) {
+	if len(ranges) == 0 {
+		return tooltypes.BlockRange{}, fmt.Errorf("no block ranges to intersect")
+	}
+	r := ranges[0]
+	for _, other := range ranges[1:] {
+		var err error
+		if r, err = clampBlockRange(r, other); err != nil {
+			return tooltypes.BlockRange{}, err
+		}
+	}
+	return r, nil
+}
+
+func clampBlockRange(r tooltypes.BlockRange, available tooltypes.BlockRange) (tooltypes.BlockRange, error) {
+	clamped := r
+	if clamped.Start < available.Start {
+		clamped.Start = available.Start
+	}
+	if clamped.End > available.End {
+		clamped.End = available.End
+	}
+	if clamped.Start > clamped.End {
+		return tooltypes.BlockRange{}, fmt.Errorf("block range %d-%d is not available, node serves %d-%d",
+			r.Start, r.End, available.Start, available.End)
+	}
+	if clamped != r {
+		log.Warn().Msgf("block range %d-%d clamped to %d-%d", r.Start, r.End, clamped.Start, clamped.End)
+	}
+	return clamped, nil
+}
+
+var (
+	networkBlockRangesMu sync.RWMutex
+	networkBlockRanges   = make(map[string]tooltypes.BlockRange)
+)
+
+// SetNetworkBlockRange registers the blocks available on a network, used by
+// the historical call generators of that network
+func SetNetworkBlockRange(network string, r tooltypes.BlockRange) {
+	networkBlockRangesMu.Lock()
+	defer networkBlockRangesMu.Unlock()
+	networkBlockRanges[network] = r
+}
+
+// NetworkBlockRange returns the blocks registered for a network, if any
+func NetworkBlockRange(network *string) (tooltypes.BlockRange, bool) {
+	key := ""
+	if network != nil {
+		key = *network
+	}
+
+	networkBlockRangesMu.RLock()
+	defer networkBlockRangesMu.RUnlock()
+	r, ok := networkBlockRanges[key]
+	return r, ok
+}
+
+// networkBlockRange returns the blocks registered for a network, else the
+// blocks harvested in its samples, falling back to the default historical range
+func networkBlockRange(network *string) (int64, int64) {
+	if r, ok := resolvedBlockRange(network); ok {
+		return r.Start, r.End
+	}
+	return defaultStartBlock, defaultEndBlock
+}
+
+// resolvedBlockRange returns the blocks registered for a network, else the
+// blocks harvested in its samples, if any
+func resolvedBlockRange(network *string) (tooltypes.BlockRange, bool) {
+	if r, ok := NetworkBlockRange(network); ok {
+		return r, true
+	}
+	return sampledBlockRange(network)
+}
+
+// sampledBlockRange returns the range spanned by the blocks dataset of a
+// network, which holds the recent blocks the samples were harvested from
+func sampledBlockRange(network *string) (tooltypes.BlockRange, bool) {
+	name := samples.DefaultNetwork
+	if network != nil && *network != "" {
+		name = *network
+	}
+	if !samples.Exists(name, samples.Blocks) {
+		return tooltypes.BlockRange{}, false
+	}
+
+	values, err := samples.Load(name, samples.Blocks)
+	if err != nil {
+		log.Warn().Msgf("Failed to load the block samples of %s: %v", name, err)
+		return tooltypes.BlockRange{}, false
+	}
+
+	r := tooltypes.BlockRange{Start: -1, End: -1}
+	for _, value := range values {
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || number < 0 {
+			log.Warn().Msgf("Invalid block number %q in the block samples of %s", value, name)
+			return tooltypes.BlockRange{}, false
+		}
+		if r.Start < 0 || number < r.Start {
+			r.Start = number
+		}
+		r.End = max(r.End, number)
+	}
+	return r, true
+}
@@ -0,0 +1,43 @@
+package rpc_builder
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/unifralabs/unifra-benchmark-tool/types"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// erc20BalanceOfSelector is the 4-byte selector of balanceOf(address)
+var erc20BalanceOfSelector = crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+
+// GenerateCallsEthCall generates ERC20 balanceOf(holder) calls against contracts,
+// the most common read-only eth_call shape seen by nodes.
+func GenerateCallsEthCall(
+	nCalls int,
+	network *string,
+	contracts []string,
+	holders []string,
+	blockNumbers []int64,
+	randomSeed *tooltypes.RandomSeed,
+) ([]*types.JsonrpcMessage, error) {
+	contracts, blockNumbers, err := generateAddressesAndBlocks(nCalls, network, contracts, blockNumbers, GenerateContractAddresses, randomSeed)
+	if err != nil {
+		return nil, err
+	}
+	if holders == nil {
+		holders, err = GenerateEOAs(nCalls, network, randomSeed)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	calls := make([]*types.JsonrpcMessage, len(contracts))
+	for i := range contracts {
+		blockNumber := rpc.BlockNumber(blockNumbers[i])
+		holder := common.HexToAddress(holders[i%len(holders)])
+		data := append(append([]byte{}, erc20BalanceOfSelector...), common.LeftPadBytes(holder.Bytes(), 32)...)
+		calls[i] = ConstructEthCall(common.HexToAddress(contracts[i]), data, &blockNumber)
+	}
+	return calls, nil
+}
@@ -0,0 +1,104 @@
+package rpc_builder
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/unifralabs/unifra-benchmark-tool/types"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+func GenerateCallsEthGetTransactionCount(
+	nCalls int,
+	network *string,
+	addresses []string,
+	blockNumbers []int64,
+	randomSeed *tooltypes.RandomSeed,
+) ([]*types.JsonrpcMessage, error) {
+	addresses, blockNumbers, err := generateAddressesAndBlocks(nCalls, network, addresses, blockNumbers, GenerateEOAs, randomSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]*types.JsonrpcMessage, len(addresses))
+	for i := range addresses {
+		blockNumber := rpc.BlockNumber(blockNumbers[i])
+		calls[i] = ConstructEthGetTransactionCount(common.HexToAddress(addresses[i]), &blockNumber)
+	}
+	return calls, nil
+}
+
+func GenerateCallsEthGetCode(
+	nCalls int,
+	network *string,
+	addresses []string,
+	blockNumbers []int64,
+	randomSeed *tooltypes.RandomSeed,
+) ([]*types.JsonrpcMessage, error) {
+	addresses, blockNumbers, err := generateAddressesAndBlocks(nCalls, network, addresses, blockNumbers, GenerateContractAddresses, randomSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]*types.JsonrpcMessage, len(addresses))
+	for i := range addresses {
+		blockNumber := rpc.BlockNumber(blockNumbers[i])
+		calls[i] = ConstructEthGetCode(common.HexToAddress(addresses[i]), &blockNumber)
+	}
+	return calls, nil
+}
+
+// GenerateCallsEthGetStorageAt reads the first storage slots of contracts,
+// where most contracts keep their owner and supply variables.
+func GenerateCallsEthGetStorageAt(
+	nCalls int,
+	network *string,
+	addresses []string,
+	blockNumbers []int64,
+	randomSeed *tooltypes.RandomSeed,
+) ([]*types.JsonrpcMessage, error) {
+	addresses, blockNumbers, err := generateAddressesAndBlocks(nCalls, network, addresses, blockNumbers, GenerateContractAddresses, randomSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]*types.JsonrpcMessage, len(addresses))
+	for i := range addresses {
+		blockNumber := rpc.BlockNumber(blockNumbers[i])
+		slot := common.BytesToHash([]byte{byte(i % 8)})
+		calls[i] = ConstructEthGetStorageAt(common.HexToAddress(addresses[i]), slot, &blockNumber)
+	}
+	return calls, nil
+}
+
+func generateAddressesAndBlocks(
+	nCalls int,
+	network *string,
+	addresses []string,
+	blockNumbers []int64,
+	generateAddresses func(int, *string, *tooltypes.RandomSeed) ([]string, error),
+	randomSeed *tooltypes.RandomSeed,
+) ([]string, []int64, error) {
+	if blockNumbers == nil {
+		var err error
+		startBlock, endBlock := networkBlockRange(network)
+		blockNumbers, err = GenerateBlockNumbers(
+			nCalls,
+			startBlock,
+			endBlock,
+			true,
+			randomSeed,
+			network,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if addresses == nil {
+		var err error
+		addresses, err = generateAddresses(nCalls, network, randomSeed)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return addresses, blockNumbers, nil
+}
@@ -32,10 +32,11 @@ func GenerateCallsEthGetEthBalance(
 ) ([]*types.JsonrpcMessage, error) {
 	if blockNumbers == nil {
 		var err error
+		startBlock, endBlock := networkBlockRange(network)
 		blockNumbers, err = GenerateBlockNumbers(
 			nCalls,
-			10_000_000,
-			16_000_000,
+			startBlock,
+			endBlock,
 			true,
 			randomSeed,
 			network,
@@ -0,0 +1,50 @@
+package rpc_builder
+
+import (
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/unifralabs/unifra-benchmark-tool/types"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+func GenerateCallsEthBlockNumber(
+	nCalls int,
+	network *string,
+	randomSeed *tooltypes.RandomSeed,
+) ([]*types.JsonrpcMessage, error) {
+	calls := make([]*types.JsonrpcMessage, nCalls)
+	for i := range calls {
+		calls[i] = ConstructEthBlockNumber()
+	}
+	return calls, nil
+}
+
+func GenerateCallsEthGetBlockByNumber(
+	nCalls int,
+	network *string,
+	blockNumbers []int64,
+	includeFullTransactions bool,
+	randomSeed *tooltypes.RandomSeed,
+) ([]*types.JsonrpcMessage, error) {
+	if blockNumbers == nil {
+		var err error
+		startBlock, endBlock := networkBlockRange(network)
+		blockNumbers, err = GenerateBlockNumbers(
+			nCalls,
+			startBlock,
+			endBlock,
+			false,
+			randomSeed,
+			network,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	calls := make([]*types.JsonrpcMessage, len(blockNumbers))
+	for i := range blockNumbers {
+		blockNumber := rpc.BlockNumber(blockNumbers[i])
+		calls[i] = ConstructEthGetBlockByNumber(&blockNumber, includeFullTransactions)
+	}
+	return calls, nil
+}
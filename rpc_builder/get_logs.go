@@ -0,0 +1,54 @@
+package rpc_builder
+
+import (
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/unifralabs/unifra-benchmark-tool/types"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// defaultLogsBlockRange is the number of blocks spanned by each eth_getLogs filter
+const defaultLogsBlockRange int64 = 10
+
+func GenerateCallsEthGetLogs(
+	nCalls int,
+	network *string,
+	blockNumbers []int64,
+	blockRange int64,
+	randomSeed *tooltypes.RandomSeed,
+) ([]*types.JsonrpcMessage, error) {
+	if blockRange <= 0 {
+		blockRange = defaultLogsBlockRange
+	}
+	if blockNumbers == nil {
+		var err error
+		startBlock, endBlock := networkBlockRange(network)
+		if endBlock-blockRange+1 > startBlock {
+			endBlock = endBlock - blockRange + 1
+		}
+		blockNumbers, err = GenerateBlockNumbers(
+			nCalls,
+			startBlock,
+			endBlock,
+			false,
+			randomSeed,
+			network,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Filters stop at the end of the resolved range, the chain head of last:N
+	// ranges. The default historical range ends at no particular head.
+	resolved, clamp := resolvedBlockRange(network)
+
+	calls := make([]*types.JsonrpcMessage, len(blockNumbers))
+	for i, number := range blockNumbers {
+		toBlock := number + blockRange - 1
+		if clamp && toBlock > resolved.End {
+			toBlock = max(resolved.End, number)
+		}
+		calls[i] = ConstructEthGetLogs(rpc.BlockNumber(number), rpc.BlockNumber(toBlock), nil, nil)
+	}
+	return calls, nil
+}
@@ -0,0 +1,41 @@
+package rpc_builder
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unifralabs/unifra-benchmark-tool/types"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+func GenerateCallsEthGetTransactionByHash(
+	nCalls int,
+	network *string,
+	transactionHashes []string,
+	randomSeed *tooltypes.RandomSeed,
+) ([]*types.JsonrpcMessage, error) {
+	if transactionHashes == nil {
+		transactionHashes = GenerateTransactionHashes(nCalls, network, randomSeed)
+	}
+
+	calls := make([]*types.JsonrpcMessage, len(transactionHashes))
+	for i := range transactionHashes {
+		calls[i] = ConstructEthGetTransactionByHash(common.HexToHash(transactionHashes[i]))
+	}
+	return calls, nil
+}
+
+func GenerateCallsEthGetTransactionReceipt(
+	nCalls int,
+	network *string,
+	transactionHashes []string,
+	randomSeed *tooltypes.RandomSeed,
+) ([]*types.JsonrpcMessage, error) {
+	if transactionHashes == nil {
+		transactionHashes = GenerateTransactionHashes(nCalls, network, randomSeed)
+	}
+
+	calls := make([]*types.JsonrpcMessage, len(transactionHashes))
+	for i := range transactionHashes {
+		calls[i] = ConstructEthGetTransactionReceipt(common.HexToHash(transactionHashes[i]))
+	}
+	return calls, nil
+}
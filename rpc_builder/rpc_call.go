@@ -2,11 +2,16 @@ package rpc_builder
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/unifralabs/unifra-benchmark-tool/types"
 	"github.com/unifralabs/unifra-benchmark-tool/utils"
 )
 
+func ConstructEthBlockNumber() *types.JsonrpcMessage {
+	return utils.NewJsonrpcMessage("eth_blockNumber", []interface{}{})
+}
+
 func ConstructEthGetBlockByNumber(blockNumber *rpc.BlockNumber, includeFullTransactions bool) *types.JsonrpcMessage {
 	encodedBlockNumber := encodeBlockNumber(*blockNumber)
 
@@ -23,6 +28,68 @@ func ConstructEthGetBalance(address common.Address, blockNumber *rpc.BlockNumber
 	return utils.NewJsonrpcMessage("eth_getBalance", []interface{}{address, encodedBlockNumber})
 }
 
+func ConstructEthGetTransactionCount(address common.Address, blockNumber *rpc.BlockNumber) *types.JsonrpcMessage {
+	if blockNumber == nil {
+		latest := rpc.LatestBlockNumber
+		blockNumber = &latest
+	}
+	encodedBlockNumber := encodeBlockNumber(*blockNumber)
+	return utils.NewJsonrpcMessage("eth_getTransactionCount", []interface{}{address, encodedBlockNumber})
+}
+
+func ConstructEthGetCode(address common.Address, blockNumber *rpc.BlockNumber) *types.JsonrpcMessage {
+	if blockNumber == nil {
+		latest := rpc.LatestBlockNumber
+		blockNumber = &latest
+	}
+	encodedBlockNumber := encodeBlockNumber(*blockNumber)
+	return utils.NewJsonrpcMessage("eth_getCode", []interface{}{address, encodedBlockNumber})
+}
+
+func ConstructEthGetStorageAt(address common.Address, slot common.Hash, blockNumber *rpc.BlockNumber) *types.JsonrpcMessage {
+	if blockNumber == nil {
+		latest := rpc.LatestBlockNumber
+		blockNumber = &latest
+	}
+	encodedBlockNumber := encodeBlockNumber(*blockNumber)
+	return utils.NewJsonrpcMessage("eth_getStorageAt", []interface{}{address, slot, encodedBlockNumber})
+}
+
+func ConstructEthCall(to common.Address, data []byte, blockNumber *rpc.BlockNumber) *types.JsonrpcMessage {
+	if blockNumber == nil {
+		latest := rpc.LatestBlockNumber
+		blockNumber = &latest
+	}
+	encodedBlockNumber := encodeBlockNumber(*blockNumber)
+	transaction := map[string]interface{}{
+		"to":   to,
+		"data": hexutil.Encode(data),
+	}
+	return utils.NewJsonrpcMessage("eth_call", []interface{}{transaction, encodedBlockNumber})
+}
+
+func ConstructEthGetLogs(fromBlock rpc.BlockNumber, toBlock rpc.BlockNumber, addresses []common.Address, topics [][]common.Hash) *types.JsonrpcMessage {
+	filter := map[string]interface{}{
+		"fromBlock": encodeBlockNumber(fromBlock),
+		"toBlock":   encodeBlockNumber(toBlock),
+	}
+	if len(addresses) > 0 {
+		filter["address"] = addresses
+	}
+	if len(topics) > 0 {
+		filter["topics"] = topics
+	}
+	return utils.NewJsonrpcMessage("eth_getLogs", []interface{}{filter})
+}
+
+func ConstructEthGetTransactionByHash(hash common.Hash) *types.JsonrpcMessage {
+	return utils.NewJsonrpcMessage("eth_getTransactionByHash", []interface{}{hash})
+}
+
+func ConstructEthGetTransactionReceipt(hash common.Hash) *types.JsonrpcMessage {
+	return utils.NewJsonrpcMessage("eth_getTransactionReceipt", []interface{}{hash})
+}
+
 func encodeBlockNumber(blockNumber rpc.BlockNumber) string {
 	if blockNumber == rpc.LatestBlockNumber {
 		return "latest"
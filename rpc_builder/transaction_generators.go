@@ -1,17 +1,20 @@
 package rpc_builder
 
 import (
-	"math/rand"
-	"time"
+	"sync"
 
+	"github.com/rs/zerolog/log"
+	"github.com/unifralabs/unifra-benchmark-tool/samples"
 	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+	"golang.org/x/exp/rand"
 )
 
 // GenerateTransactionHashes generates a sequence of transaction hashes
 func GenerateTransactionHashes(n int, network *string, randomSeed *tooltypes.RandomSeed) []string {
 	return LoadSamples(LoadSamplesParams{
 		Network:    network,
-		Datatype:   "transactions",
+		Datatype:   samples.Transactions,
 		N:          n,
 		RandomSeed: randomSeed,
 	})
@@ -20,46 +23,40 @@ func GenerateTransactionHashes(n int, network *string, randomSeed *tooltypes.Ran
 // LoadSamplesParams represents the parameters for LoadSamples
 type LoadSamplesParams struct {
 	Network    *string
-	Datatype   string
+	Datatype   samples.Datatype
 	N          int
 	RandomSeed *tooltypes.RandomSeed
 }
 
-// LoadSamples loads sample data
+// missingSamples records the datasets already reported as missing
+var missingSamples sync.Map
+
+// LoadSamples draws hash samples from the dataset of the network, falling
+// back to random hashes when no dataset is available
 func LoadSamples(params LoadSamplesParams) []string {
-	// Initialize random number generator
-	var r *rand.Rand
-	if params.RandomSeed != nil {
-		r = rand.New(rand.NewSource(int64(*params.RandomSeed)))
-	} else {
-		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	if chosen, ok := sampleDataset(params); ok {
+		return chosen
 	}
 
-	// TODO: Implement actual sample loading logic
-	// This is a placeholder implementation
-	samples := make([]string, params.N)
+	r, _ := utils.GetRNG(params.RandomSeed)
+	values := make([]string, params.N)
 	for i := 0; i < params.N; i++ {
-		samples[i] = generateRandomHash(r)
+		values[i] = generateRandomHash(r)
 	}
 
-	return samples
+	return values
 }
 
 // generateRandomHash generates a random hash-like string
 func generateRandomHash(r *rand.Rand) string {
-	const charset = "abcdef0123456789"
-	hash := make([]byte, 64)
-	for i := range hash {
-		hash[i] = charset[r.Intn(len(charset))]
-	}
-	return "0x" + string(hash)
+	return "0x" + generateRandomHexString(r, 64)
 }
 
 // GenerateContractAddresses generates a sequence of contract addresses
 func GenerateContractAddresses(n int, network *string, randomSeed *tooltypes.RandomSeed) ([]string, error) {
 	return LoadAddressSamples(LoadSamplesParams{
 		Network:    network,
-		Datatype:   "contracts",
+		Datatype:   samples.Contracts,
 		N:          n,
 		RandomSeed: randomSeed,
 	}), nil
@@ -69,34 +66,58 @@ func GenerateContractAddresses(n int, network *string, randomSeed *tooltypes.Ran
 func GenerateEOAs(n int, network *string, randomSeed *tooltypes.RandomSeed) ([]string, error) {
 	return LoadAddressSamples(LoadSamplesParams{
 		Network:    network,
-		Datatype:   "eoas",
+		Datatype:   samples.EOAs,
 		N:          n,
 		RandomSeed: randomSeed,
 	}), nil
 }
 
-// LoadSamples loads sample data
+// LoadAddressSamples draws address samples from the dataset of the network,
+// falling back to random addresses when no dataset is available
 func LoadAddressSamples(params LoadSamplesParams) []string {
-	// TODO: Implement actual sample loading logic
-	// This is a placeholder implementation
-	samples := make([]string, params.N)
+	if chosen, ok := sampleDataset(params); ok {
+		return chosen
+	}
+
+	r, _ := utils.GetRNG(params.RandomSeed)
+	values := make([]string, params.N)
 	for i := 0; i < params.N; i++ {
-		samples[i] = generateRandomAddress()
+		values[i] = generateRandomAddress(r)
+	}
+	return values
+}
+
+func sampleDataset(params LoadSamplesParams) ([]string, bool) {
+	network := samples.DefaultNetwork
+	if params.Network != nil && *params.Network != "" {
+		network = *params.Network
+	}
+
+	values, err := samples.Load(network, params.Datatype)
+	if err != nil {
+		path := samples.Path(network, params.Datatype)
+		if _, reported := missingSamples.LoadOrStore(path, true); !reported {
+			log.Warn().Msgf("No %s samples for %s (%v), using random values", params.Datatype, network, err)
+		}
+		return nil, false
+	}
+
+	chosen, err := samples.Sample(values, params.N, params.RandomSeed)
+	if err != nil {
+		return nil, false
 	}
-	return samples
+	return chosen, true
 }
 
 // generateRandomAddress generates a random Ethereum-like address
-func generateRandomAddress() string {
-	// Placeholder implementation
-	return "0x" + generateRandomHexString(40)
+func generateRandomAddress(r *rand.Rand) string {
+	return "0x" + generateRandomHexString(r, 40)
 }
 
 // generateRandomHexString generates a random hex string of given length
-func generateRandomHexString(length int) string {
+func generateRandomHexString(r *rand.Rand, length int) string {
 	const charset = "0123456789abcdef"
 	result := make([]byte, length)
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	for i := range result {
 		result[i] = charset[r.Intn(len(charset))]
 	}
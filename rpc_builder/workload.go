@@ -0,0 +1,285 @@
+package rpc_builder
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/unifralabs/unifra-benchmark-tool/types"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// CallsGenerator generates nCalls calls of a single JSON-RPC method. params is
+// never nil, its empty fields are drawn from the sample datasets.
+type CallsGenerator func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error)
+
+// CallsGenerators are the JSON-RPC methods that can be part of a workload
+var CallsGenerators = map[string]CallsGenerator{
+	"eth_blockNumber": func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error) {
+		return GenerateCallsEthBlockNumber(nCalls, network, randomSeed)
+	},
+	"eth_call": func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error) {
+		return GenerateCallsEthCall(nCalls, network, cycleParam(params.Addresses, nCalls), cycleParam(params.Holders, nCalls), cycleParam(params.Blocks, nCalls), randomSeed)
+	},
+	"eth_getBalance": func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error) {
+		return GenerateCallsEthGetEthBalance(nCalls, network, cycleParam(params.Addresses, nCalls), cycleParam(params.Blocks, nCalls), randomSeed)
+	},
+	"eth_getBlockByNumber": func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error) {
+		return GenerateCallsEthGetBlockByNumber(nCalls, network, cycleParam(params.Blocks, nCalls), params.FullTransactions, randomSeed)
+	},
+	"eth_getCode": func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error) {
+		return GenerateCallsEthGetCode(nCalls, network, cycleParam(params.Addresses, nCalls), cycleParam(params.Blocks, nCalls), randomSeed)
+	},
+	"eth_getLogs": func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error) {
+		return GenerateCallsEthGetLogs(nCalls, network, cycleParam(params.Blocks, nCalls), params.LogsBlockRange, randomSeed)
+	},
+	"eth_getStorageAt": func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error) {
+		return GenerateCallsEthGetStorageAt(nCalls, network, cycleParam(params.Addresses, nCalls), cycleParam(params.Blocks, nCalls), randomSeed)
+	},
+	"eth_getTransactionByHash": func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error) {
+		return GenerateCallsEthGetTransactionByHash(nCalls, network, cycleParam(params.Transactions, nCalls), randomSeed)
+	},
+	"eth_getTransactionCount": func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error) {
+		return GenerateCallsEthGetTransactionCount(nCalls, network, cycleParam(params.Addresses, nCalls), cycleParam(params.Blocks, nCalls), randomSeed)
+	},
+	"eth_getTransactionReceipt": func(nCalls int, network *string, params *tooltypes.WorkloadParams, randomSeed *tooltypes.RandomSeed) ([]*types.JsonrpcMessage, error) {
+		return GenerateCallsEthGetTransactionReceipt(nCalls, network, cycleParam(params.Transactions, nCalls), randomSeed)
+	},
+}
+
+// workloadMethodParams are the workload params used by each method
+var workloadMethodParams = map[string][]string{
+	"eth_blockNumber":           {},
+	"eth_call":                  {"addresses", "holders", "blocks"},
+	"eth_getBalance":            {"addresses", "blocks"},
+	"eth_getBlockByNumber":      {"blocks", "full_transactions"},
+	"eth_getCode":               {"addresses", "blocks"},
+	"eth_getLogs":               {"blocks", "logs_block_range"},
+	"eth_getStorageAt":          {"addresses", "blocks"},
+	"eth_getTransactionByHash":  {"transactions"},
+	"eth_getTransactionCount":   {"addresses", "blocks"},
+	"eth_getTransactionReceipt": {"transactions"},
+}
+
+// cycleParam repeats the values of a workload param to fill n calls, nil
+// values meaning that the calls draw from the sample datasets
+func cycleParam[T any](values []T, n int) []T {
+	if len(values) == 0 {
+		return nil
+	}
+	cycled := make([]T, n)
+	for i := range cycled {
+		cycled[i] = values[i%len(values)]
+	}
+	return cycled
+}
+
+// setWorkloadParams returns the names of the params that are set
+func setWorkloadParams(params *tooltypes.WorkloadParams) []string {
+	names := []string{}
+	if params == nil {
+		return names
+	}
+	if len(params.Addresses) > 0 {
+		names = append(names, "addresses")
+	}
+	if len(params.Holders) > 0 {
+		names = append(names, "holders")
+	}
+	if len(params.Transactions) > 0 {
+		names = append(names, "transactions")
+	}
+	if len(params.Blocks) > 0 {
+		names = append(names, "blocks")
+	}
+	if params.LogsBlockRange != 0 {
+		names = append(names, "logs_block_range")
+	}
+	if params.FullTransactions {
+		names = append(names, "full_transactions")
+	}
+	return names
+}
+
+// DefaultWorkload is used when no workload is configured
+var DefaultWorkload = tooltypes.Workload{{Method: "eth_getBalance", Weight: 1}}
+
+// ParseWorkload parses a workload specification such as
+// "eth_call=40,eth_getBalance=20,eth_getLogs=10". A method without a weight
+// has a weight of 1.
+func ParseWorkload(spec string) (tooltypes.Workload, error) {
+	workload := tooltypes.Workload{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		method, weight := entry, 1.0
+		if strings.Contains(entry, "=") {
+			parts := strings.SplitN(entry, "=", 2)
+			method = strings.TrimSpace(parts[0])
+			var err error
+			weight, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid weight for %s: %w", method, err)
+			}
+		}
+		workload = append(workload, tooltypes.WorkloadMethod{Method: method, Weight: weight})
+	}
+
+	if err := ValidateWorkload(workload); err != nil {
+		return nil, err
+	}
+	return workload, nil
+}
+
+// ValidateWorkload checks that every method of a workload is supported and has a positive weight
+func ValidateWorkload(workload tooltypes.Workload) error {
+	if len(workload) == 0 {
+		return fmt.Errorf("workload must contain at least one method")
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range workload {
+		if _, ok := CallsGenerators[m.Method]; !ok {
+			return fmt.Errorf("unsupported workload method: %s", m.Method)
+		}
+		if seen[m.Method] {
+			return fmt.Errorf("duplicate workload method: %s", m.Method)
+		}
+		if m.Weight <= 0 || math.IsNaN(m.Weight) || math.IsInf(m.Weight, 0) {
+			return fmt.Errorf("weight of %s must be positive", m.Method)
+		}
+		for _, name := range setWorkloadParams(m.Params) {
+			if !slices.Contains(workloadMethodParams[m.Method], name) {
+				return fmt.Errorf("%s does not use the %s param", m.Method, name)
+			}
+		}
+		if m.Params != nil && m.Params.LogsBlockRange < 0 {
+			return fmt.Errorf("logs block range of %s must be positive", m.Method)
+		}
+		seen[m.Method] = true
+	}
+	return nil
+}
+
+// GenerateTestWorkload generates a sequence of VegetaAttacks for the mixed workload of the parameters
+func GenerateTestWorkload(params tooltypes.TestGenerationParameters) ([]tooltypes.VegetaAttack, error) {
+	workload := params.Workload
+	if len(workload) == 0 {
+		workload = DefaultWorkload
+	}
+
+	// A load test mode replaces the explicit rates and durations
+	if params.Mode != nil {
+		rates, durations, err := tooltypes.GenerateLoadTestSchedule(*params.Mode)
+		if err != nil {
+			return nil, err
+		}
+		params.Rates, params.Durations = rates, durations
+	}
+
+	nCalls, err := tooltypes.EstimateCallCount(params.Rates, params.Durations, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	calls, err := GenerateCallsWorkload(nCalls, workload, &params.Network, &params.RandomSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	return tooltypes.CreateLoadTest(calls,
+		params.Rates, params.Durations, params.VegetaArgs, true)
+}
+
+// GenerateCallsWorkload generates nCalls calls whose methods follow the
+// weights of the workload, interleaved in a seed-reproducible order.
+func GenerateCallsWorkload(
+	nCalls int,
+	workload tooltypes.Workload,
+	network *string,
+	randomSeed *tooltypes.RandomSeed,
+) ([]*types.JsonrpcMessage, error) {
+	if err := ValidateWorkload(workload); err != nil {
+		return nil, err
+	}
+
+	counts := allocateWorkloadCalls(nCalls, workload)
+
+	methodCalls := make([][]*types.JsonrpcMessage, len(workload))
+	order := make([]int, 0, nCalls)
+	for i, m := range workload {
+		// Derive a distinct seed per method so their samples are not correlated
+		var methodSeed *tooltypes.RandomSeed
+		if randomSeed != nil {
+			seed := *randomSeed + tooltypes.RandomSeed(i)
+			methodSeed = &seed
+		}
+
+		params := m.Params
+		if params == nil {
+			params = &tooltypes.WorkloadParams{}
+		}
+
+		calls, err := CallsGenerators[m.Method](counts[i], network, params, methodSeed)
+		if err != nil {
+			return nil, fmt.Errorf("error generating %s calls: %w", m.Method, err)
+		}
+		methodCalls[i] = calls
+
+		for range calls {
+			order = append(order, i)
+		}
+	}
+
+	rng, err := utils.GetRNG(randomSeed)
+	if err != nil {
+		return nil, err
+	}
+	rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+	calls := make([]*types.JsonrpcMessage, 0, len(order))
+	next := make([]int, len(workload))
+	for _, i := range order {
+		calls = append(calls, methodCalls[i][next[i]])
+		next[i]++
+	}
+	return calls, nil
+}
+
+// allocateWorkloadCalls splits nCalls across the workload methods
+// proportionally to their weights, using the largest remainder method.
+func allocateWorkloadCalls(nCalls int, workload tooltypes.Workload) []int {
+	totalWeight := 0.0
+	for _, m := range workload {
+		totalWeight += m.Weight
+	}
+
+	counts := make([]int, len(workload))
+	remainders := make([]float64, len(workload))
+	allocated := 0
+	for i, m := range workload {
+		exact := float64(nCalls) * m.Weight / totalWeight
+		counts[i] = int(math.Floor(exact))
+		remainders[i] = exact - float64(counts[i])
+		allocated += counts[i]
+	}
+
+	indexes := make([]int, len(workload))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool { return remainders[indexes[a]] > remainders[indexes[b]] })
+	for i := 0; allocated < nCalls; i++ {
+		counts[indexes[i%len(indexes)]]++
+		allocated++
+	}
+
+	return counts
+}
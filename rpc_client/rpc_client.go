@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -15,6 +16,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
 )
 
 type RpcClient struct {
@@ -93,6 +96,59 @@ func (e *RpcClient) GetGasPrice() (*big.Int, error) {
 	return gasPrice, nil
 }
 
+const (
+	// feeHistoryBlocks is the number of recent blocks whose tips are sampled
+	feeHistoryBlocks = 20
+	// feeHistoryPercentile is the percentile of the tips paid in each block
+	feeHistoryPercentile = 50
+)
+
+// SuggestFees suggests the fees of a transaction in the given fee mode. In
+// the dynamic fee mode, the tip is the median of the tips paid in recent
+// blocks and the fee cap covers a doubling of the next base fee.
+func (e *RpcClient) SuggestFees(mode tooltypes.FeeMode) (*tooltypes.TxFees, error) {
+	if mode != tooltypes.DynamicFees {
+		gasPrice, err := e.GetGasPrice()
+		if err != nil {
+			return nil, err
+		}
+		return &tooltypes.TxFees{Mode: mode, GasPrice: gasPrice}, nil
+	}
+
+	history, err := e.client.FeeHistory(context.Background(), feeHistoryBlocks, nil, []float64{feeHistoryPercentile})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee history: %v", err)
+	}
+	if len(history.BaseFee) == 0 || history.BaseFee[len(history.BaseFee)-1] == nil {
+		return nil, fmt.Errorf("node does not report base fees, use the legacy fee mode")
+	}
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	tips := []*big.Int{}
+	for _, rewards := range history.Reward {
+		if len(rewards) > 0 && rewards[0] != nil && rewards[0].Sign() > 0 {
+			tips = append(tips, rewards[0])
+		}
+	}
+
+	var tip *big.Int
+	if len(tips) > 0 {
+		sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+		tip = new(big.Int).Set(tips[len(tips)/2])
+	} else {
+		// Recent blocks paid no tips, fall back to the suggestion of the node
+		tip, err = e.client.SuggestGasTipCap(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get gas tip cap: %v", err)
+		}
+	}
+
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+	feeCap.Add(feeCap, tip)
+
+	return &tooltypes.TxFees{Mode: mode, GasTipCap: tip, GasFeeCap: feeCap}, nil
+}
+
 func (ec *RpcClient) BuildTransferTx(to common.Address, value *big.Int, gasLimit uint64, opts *bind.TransactOpts) (tx *types.Transaction, err error) {
 	ctx := opts.Context
 	if ctx == nil {
@@ -109,23 +165,35 @@ func (ec *RpcClient) BuildTransferTx(to common.Address, value *big.Int, gasLimit
 		nonce = opts.Nonce.Uint64()
 	}
 
-	if opts.GasPrice == nil {
-		price, err := ec.client.SuggestGasPrice(ctx)
-		if err != nil {
-			return nil, err
+	var rawTx *types.Transaction
+	if opts.GasFeeCap != nil {
+		rawTx = types.NewTx(&types.DynamicFeeTx{
+			Nonce:     nonce,
+			To:        &to,
+			GasTipCap: opts.GasTipCap,
+			GasFeeCap: opts.GasFeeCap,
+			Gas:       gasLimit,
+			Value:     value,
+			Data:      []byte{},
+		})
+	} else {
+		if opts.GasPrice == nil {
+			price, err := ec.client.SuggestGasPrice(ctx)
+			if err != nil {
+				return nil, err
+			}
+			opts.GasPrice = price
 		}
-		opts.GasPrice = price
-	}
 
-	baseTx := types.LegacyTx{
-		Nonce:    nonce,
-		To:       &to,
-		GasPrice: opts.GasPrice,
-		Gas:      gasLimit,
-		Value:    value,
-		Data:     []byte{},
+		rawTx = types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			To:       &to,
+			GasPrice: opts.GasPrice,
+			Gas:      gasLimit,
+			Value:    value,
+			Data:     []byte{},
+		})
 	}
-	rawTx := types.NewTx(&baseTx)
 
 	signedTx, err := opts.Signer(opts.From, rawTx)
 	if err != nil {
@@ -187,6 +255,21 @@ func (e *RpcClient) EstimateGasForContractCall(fromAddress common.Address, toAdd
 	return gas, nil
 }
 
+// CreateAccessList returns the addresses and storage keys that a call
+// accesses, from eth_createAccessList. A reverting call still returns the
+// accesses made before the revert.
+func (e *RpcClient) CreateAccessList(msg ethereum.CallMsg) (types.AccessList, error) {
+	accessList, _, _, err := gethclient.New(e.client.Client()).CreateAccessList(context.Background(), msg)
+	if err != nil {
+		return nil, err
+	}
+	if accessList == nil {
+		return types.AccessList{}, nil
+	}
+
+	return *accessList, nil
+}
+
 // SendRawTransaction sends a raw transaction to the ETH network
 func (e *RpcClient) SendRawTransaction(rawTx string) (string, error) {
 	tx, err := hexutil.Decode(rawTx)
@@ -226,3 +309,48 @@ func (e *RpcClient) GetClientVersion() (string, error) {
 	}
 	return result, nil
 }
+
+func (e *RpcClient) GetBlockNumber() (uint64, error) {
+	blockNumber, err := e.client.BlockNumber(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block number: %v", err)
+	}
+
+	return blockNumber, nil
+}
+
+// HasStateAt reports whether the node can serve the state of the given block
+func (e *RpcClient) HasStateAt(blockNumber uint64) bool {
+	_, err := e.client.BalanceAt(context.Background(), common.Address{}, new(big.Int).SetUint64(blockNumber))
+	return err == nil
+}
+
+// GetChainInfo returns the head of the node and the earliest block whose
+// state it still serves, found by binary search since pruned nodes only keep
+// the state of recent blocks
+func (e *RpcClient) GetChainInfo() (*tooltypes.ChainInfo, error) {
+	head, err := e.GetBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	if !e.HasStateAt(head) {
+		return nil, fmt.Errorf("state of head block %d is not available", head)
+	}
+
+	if e.HasStateAt(0) {
+		return &tooltypes.ChainInfo{Head: head, EarliestState: 0, Archive: true}, nil
+	}
+
+	low, high := uint64(1), head
+	for low < high {
+		mid := low + (high-low)/2
+		if e.HasStateAt(mid) {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	return &tooltypes.ChainInfo{Head: head, EarliestState: low, Archive: false}, nil
+}
@@ -0,0 +1,122 @@
+package samples
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rs/zerolog/log"
+	"github.com/schollz/progressbar/v3"
+)
+
+// MaxHarvestedSamples caps the size of each harvested dataset
+const MaxHarvestedSamples = 100_000
+
+// harvestedBlock only decodes the transaction fields needed for samples, so
+// that transaction types unknown to go-ethereum (e.g. L2 deposits) don't fail.
+type harvestedBlock struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Transactions []struct {
+		Hash  common.Hash     `json:"hash"`
+		From  common.Address  `json:"from"`
+		To    *common.Address `json:"to"`
+		Input hexutil.Bytes   `json:"input"`
+	} `json:"transactions"`
+}
+
+// Harvest scans the blocks [startBlock, endBlock] of a node and stores the
+// transaction hashes, contract addresses, EOAs and non-empty block numbers
+// found as the datasets of the network.
+func Harvest(client *ethclient.Client, network string, startBlock, endBlock uint64) error {
+	if endBlock < startBlock {
+		return fmt.Errorf("invalid block range %d-%d", startBlock, endBlock)
+	}
+
+	log.Info().Msgf("Harvesting %s samples from blocks %d-%d...", network, startBlock, endBlock)
+	bar := progressbar.Default(int64(endBlock - startBlock + 1))
+
+	var (
+		transactions, blocks []string
+		eoas, candidates     []string
+		seenEOAs             = make(map[common.Address]bool)
+		seenCandidates       = make(map[common.Address]bool)
+	)
+
+	for number := startBlock; number <= endBlock; number++ {
+		var block *harvestedBlock
+		err := client.Client().CallContext(context.Background(), &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), true)
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", number, err)
+		}
+		bar.Add(1)
+		if block == nil || len(block.Transactions) == 0 {
+			continue
+		}
+
+		blocks = append(blocks, strconv.FormatUint(number, 10))
+		for _, tx := range block.Transactions {
+			if len(transactions) < MaxHarvestedSamples {
+				transactions = append(transactions, tx.Hash.Hex())
+			}
+			if !seenEOAs[tx.From] && len(eoas) < MaxHarvestedSamples {
+				seenEOAs[tx.From] = true
+				eoas = append(eoas, tx.From.Hex())
+			}
+			// Only calls with input data can target a contract
+			if tx.To != nil && len(tx.Input) > 0 && !seenCandidates[*tx.To] && len(candidates) < MaxHarvestedSamples {
+				seenCandidates[*tx.To] = true
+				candidates = append(candidates, tx.To.Hex())
+			}
+		}
+	}
+
+	contracts, err := filterContracts(client, candidates, endBlock)
+	if err != nil {
+		return err
+	}
+
+	datasets := map[Datatype][]string{
+		Transactions: transactions,
+		Contracts:    contracts,
+		EOAs:         eoas,
+		Blocks:       blocks,
+	}
+	for datatype, values := range datasets {
+		if len(values) == 0 {
+			log.Warn().Msgf("No %s found in blocks %d-%d", datatype, startBlock, endBlock)
+			continue
+		}
+		if err := Save(network, datatype, values); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msgf("✅ Harvested %d transactions, %d contracts, %d EOAs from %d blocks",
+		len(transactions), len(contracts), len(eoas), len(blocks))
+	return nil
+}
+
+// filterContracts keeps the candidate addresses that hold code at the given block
+func filterContracts(client *ethclient.Client, candidates []string, blockNumber uint64) ([]string, error) {
+	log.Info().Msg("Checking contract codes...")
+	bar := progressbar.Default(int64(len(candidates)))
+
+	contracts := []string{}
+	atBlock := new(big.Int).SetUint64(blockNumber)
+	for _, candidate := range candidates {
+		code, err := client.CodeAt(context.Background(), common.HexToAddress(candidate), atBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get code of %s: %w", candidate, err)
+		}
+		if len(code) > 0 {
+			contracts = append(contracts, candidate)
+		}
+		bar.Add(1)
+	}
+
+	return contracts, nil
+}
@@ -0,0 +1,134 @@
+package samples
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+type Datatype string
+
+const (
+	Transactions Datatype = "transactions"
+	Contracts    Datatype = "contracts"
+	EOAs         Datatype = "eoas"
+	Blocks       Datatype = "blocks"
+)
+
+// DefaultNetwork is used for samples when a test does not specify a network
+const DefaultNetwork = "ethereum"
+
+// Dir is the root directory of the sample datasets. Each network has its own
+// sub-directory holding one <datatype>.txt file with one value per line.
+var Dir = filepath.Join("data", "samples")
+
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[string][]string)
+)
+
+// Path returns the file path of the dataset of a network
+func Path(network string, datatype Datatype) string {
+	if network == "" {
+		network = DefaultNetwork
+	}
+	return filepath.Join(Dir, network, string(datatype)+".txt")
+}
+
+// Load reads the dataset of a network, caching it in memory for later calls
+func Load(network string, datatype Datatype) ([]string, error) {
+	path := Path(network, datatype)
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if values, ok := cache[path]; ok {
+		return values, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		values = append(values, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read samples %s: %w", path, err)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("samples file %s is empty", path)
+	}
+
+	cache[path] = values
+	return values, nil
+}
+
+// Save writes the dataset of a network to disk and refreshes the in-memory cache
+func Save(network string, datatype Datatype, values []string) error {
+	path := Path(network, datatype)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	content := strings.Join(values, "\n")
+	if len(values) > 0 {
+		content += "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write samples %s: %w", path, err)
+	}
+
+	cacheMu.Lock()
+	cache[path] = values
+	cacheMu.Unlock()
+
+	return nil
+}
+
+// Exists reports whether a dataset of a network is available on disk
+func Exists(network string, datatype Datatype) bool {
+	info, err := os.Stat(Path(network, datatype))
+	return err == nil && info.Size() > 0
+}
+
+// Sample draws n values from a dataset. Values are drawn without replacement
+// while the dataset is large enough, and the draw only depends on the seed.
+func Sample(values []string, n int, randomSeed *tooltypes.RandomSeed) ([]string, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("cannot sample from an empty dataset")
+	}
+
+	rng, err := utils.GetRNG(randomSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	chosen := make([]string, n)
+	if n > len(values) {
+		for i := range chosen {
+			chosen[i] = values[rng.Intn(len(values))]
+		}
+	} else {
+		perm := rng.Perm(len(values))
+		for i := 0; i < n; i++ {
+			chosen[i] = values[perm[i]]
+		}
+	}
+
+	return chosen, nil
+}
@@ -0,0 +1,56 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads a scenario from a YAML (.yaml, .yml) or JSON file. Unknown
+// fields are rejected so that typos don't silently change a benchmark.
+func Load(path string) (*tooltypes.Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err = yamlToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var s tooltypes.Scenario
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	// Blob transactions carry a single blob unless told otherwise
+	if s.Tx != nil && s.Tx.BlobsPerTx == 0 {
+		s.Tx.BlobsPerTx = 1
+	}
+	return &s, nil
+}
+
+// yamlToJSON converts YAML to JSON, so that scenarios share the JSON field
+// names and decoding rules of the types they are made of
+func yamlToJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, fmt.Errorf("empty scenario")
+	}
+	return json.Marshal(value)
+}
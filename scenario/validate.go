@@ -0,0 +1,217 @@
+package scenario
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unifralabs/unifra-benchmark-tool/rpc_builder"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// Validate checks a scenario and returns every error found, prefixed by the
+// path of the offending field
+func Validate(s *tooltypes.Scenario) []error {
+	errs := []error{}
+	addErr := func(field string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if s.Name == "" {
+		addErr("name", "is required")
+	}
+
+	if len(s.Nodes) == 0 {
+		addErr("nodes", "at least one node is required")
+	}
+	names := make(map[string]bool)
+	for i, spec := range s.Nodes {
+		node, err := utils.ParseNode(spec, false)
+		if err != nil {
+			addErr(fmt.Sprintf("nodes[%d]", i), "%v", err)
+			continue
+		}
+		if u, err := url.Parse(node.URL); err != nil || u.Host == "" {
+			addErr(fmt.Sprintf("nodes[%d]", i), "invalid url %q", node.URL)
+		}
+		if names[node.Name] {
+			addErr(fmt.Sprintf("nodes[%d]", i), "duplicate node name %q", node.Name)
+		}
+		names[node.Name] = true
+	}
+
+	if s.Rpc == nil && s.Tx == nil {
+		addErr("scenario", "must define an rpc or a tx benchmark")
+	}
+	if s.Rpc != nil {
+		errs = append(errs, validateRpc(s.Rpc)...)
+	}
+	if s.Tx != nil {
+		errs = append(errs, validateTx(s.Tx)...)
+	}
+
+	return errs
+}
+
+func validateRpc(rpc *tooltypes.RpcScenario) []error {
+	errs := []error{}
+	addErr := func(field string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("rpc.%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if len(rpc.Workload) > 0 {
+		if err := rpc_builder.ValidateWorkload(rpc.Workload); err != nil {
+			addErr("workload", "%v", err)
+		}
+	}
+
+	switch {
+	case rpc.Mode != nil && len(rpc.Rates) > 0:
+		addErr("mode", "cannot be combined with explicit rates")
+	case rpc.Mode != nil:
+		if _, _, err := tooltypes.GenerateLoadTestSchedule(*rpc.Mode); err != nil {
+			addErr("mode", "%v", err)
+		}
+	case len(rpc.Rates) > 0:
+		if len(rpc.Rates) != len(rpc.Durations) {
+			addErr("durations", "%d durations given for %d rates", len(rpc.Durations), len(rpc.Rates))
+		}
+		for i, rate := range rpc.Rates {
+			if rate <= 0 {
+				addErr(fmt.Sprintf("rates[%d]", i), "must be positive")
+			}
+		}
+		for i, duration := range rpc.Durations {
+			if duration <= 0 {
+				addErr(fmt.Sprintf("durations[%d]", i), "must be positive")
+			}
+		}
+	case len(rpc.Durations) > 0:
+		addErr("durations", "given without rates")
+	}
+
+	if rpc.BlockRange != "" {
+		if _, err := rpc_builder.ParseBlockRangeSpec(rpc.BlockRange); err != nil {
+			addErr("block_range", "%v", err)
+		}
+	}
+
+	for i, deepOutput := range rpc.DeepOutput {
+		if deepOutput != tooltypes.RawDeepOutput && deepOutput != tooltypes.MetricsDeepOutput {
+			addErr(fmt.Sprintf("deep_output[%d]", i), "unknown deep output %q", deepOutput)
+		}
+	}
+
+	if rpc.Replay != "" && rpc.CapacitySearch != nil {
+		addErr("replay", "cannot be combined with a capacity search")
+	}
+	if rpc.Replay != "" && rpc.SaveTest != "" {
+		addErr("replay", "cannot be combined with save_test")
+	}
+
+	switch rpc.NodesMode {
+	case "", tooltypes.SequentialNodes, tooltypes.SimultaneousNodes:
+	default:
+		addErr("nodes_mode", "unknown nodes mode %q", rpc.NodesMode)
+	}
+	if rpc.Repeats < 0 {
+		addErr("repeats", "must not be negative")
+	}
+
+	if verify := rpc.Verify; verify != nil {
+		if rpc.CapacitySearch != nil {
+			addErr("verify", "cannot be combined with a capacity search")
+		}
+		if verify.MaxSamples < 0 {
+			addErr("verify.max_samples", "must not be negative")
+		}
+	}
+
+	if rpc.HarvestBlocks < 0 {
+		addErr("harvest_blocks", "must not be negative")
+	}
+
+	if search := rpc.CapacitySearch; search != nil {
+		if search.StartRate < 0 || search.MaxRate < 0 || search.Precision < 0 || search.ProbeDuration < 0 {
+			addErr("capacity_search", "rates and durations must not be negative")
+		}
+		if search.StartRate > 0 && search.MaxRate > 0 && search.MaxRate < search.StartRate {
+			addErr("capacity_search.max_rate", "must not be lower than the start rate")
+		}
+		if search.SLO.MinSuccess < 0 || search.SLO.MinSuccess > 1 {
+			addErr("capacity_search.slo.min_success", "must be between 0 and 1")
+		}
+		if search.SLO.MaxP99 < 0 {
+			addErr("capacity_search.slo.max_p99", "must not be negative")
+		}
+	}
+
+	return errs
+}
+
+func validateTx(tx *tooltypes.TxScenario) []error {
+	errs := []error{}
+	addErr := func(field string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("tx.%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	txType, err := tooltypes.ParseTxType(tx.Type)
+	if err != nil {
+		addErr("type", "%v", err)
+	}
+	if _, err := tooltypes.ParseFeeMode(tx.FeeMode); err != nil {
+		addErr("fee_mode", "%v", err)
+	}
+	if tx.BlobsPerTx < 1 || tx.BlobsPerTx > tooltypes.MaxBlobsPerTx {
+		addErr("blobs_per_tx", "must be between 1 and %d", tooltypes.MaxBlobsPerTx)
+	}
+	if tx.GasPerTx != 0 && !txType.IsStress() {
+		addErr("gas_per_tx", "is only used by stress workloads")
+	}
+	if txType == tooltypes.CONTRACT {
+		errs = append(errs, validateContract(tx.Contract)...)
+	} else if tx.Contract != nil {
+		addErr("contract", "is only used by contract transactions")
+	}
+	if tx.Accounts <= 0 {
+		addErr("accounts", "must be positive")
+	}
+	if tx.Transactions <= 0 {
+		addErr("transactions", "must be positive")
+	}
+	if tx.BatchSize <= 0 {
+		addErr("batch_size", "must be positive")
+	}
+
+	return errs
+}
+
+func validateContract(contract *tooltypes.ContractCallSpec) []error {
+	errs := []error{}
+	addErr := func(field string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("tx.contract%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if contract == nil {
+		addErr("", "is required by contract transactions")
+		return errs
+	}
+	if contract.ABI == "" {
+		addErr(".abi", "is required")
+	}
+	if contract.Method == "" {
+		addErr(".method", "is required")
+	}
+	if (contract.Bin == "") == (contract.Address == "") {
+		addErr("", "needs either a bin or an address")
+	}
+	if contract.Address != "" && !common.IsHexAddress(contract.Address) {
+		addErr(".address", "invalid address %s", contract.Address)
+	}
+	if contract.Address != "" && len(contract.ConstructorArgs) > 0 {
+		addErr(".constructor_args", "are only used to deploy the contract from its bin")
+	}
+
+	return errs
+}
@@ -0,0 +1,217 @@
+package scenario
+
+import (
+	"strings"
+	"testing"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// validScenario returns a scenario without errors, that the tests break one
+// field at a time
+func validScenario() *tooltypes.Scenario {
+	return &tooltypes.Scenario{
+		Name:  "test",
+		Nodes: []string{"geth=http://127.0.0.1:8545", "reth=http://127.0.0.1:8546"},
+		Rpc: &tooltypes.RpcScenario{
+			Workload:  tooltypes.Workload{{Method: "eth_blockNumber", Weight: 1}},
+			Rates:     []int{10, 20},
+			Durations: []int{5, 5},
+		},
+		Tx: &tooltypes.TxScenario{
+			Type:         "eoa",
+			Accounts:     2,
+			Transactions: 10,
+			BatchSize:    5,
+			BlobsPerTx:   1,
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *tooltypes.Scenario)
+		want   []string
+	}{
+		{name: "valid", modify: func(s *tooltypes.Scenario) {}},
+		{name: "name", modify: func(s *tooltypes.Scenario) { s.Name = "" }, want: []string{"name: is required"}},
+		{name: "no node", modify: func(s *tooltypes.Scenario) { s.Nodes = nil }, want: []string{"nodes: at least one node is required"}},
+		{name: "node url", modify: func(s *tooltypes.Scenario) { s.Nodes[1] = "reth=http://" }, want: []string{"nodes[1]: invalid url"}},
+		{name: "duplicate node", modify: func(s *tooltypes.Scenario) { s.Nodes[1] = "geth=http://127.0.0.1:8546" }, want: []string{`nodes[1]: duplicate node name "geth"`}},
+		{name: "no benchmark", modify: func(s *tooltypes.Scenario) { s.Rpc, s.Tx = nil, nil }, want: []string{"scenario: must define an rpc or a tx benchmark"}},
+
+		{
+			name:   "rpc workload",
+			modify: func(s *tooltypes.Scenario) { s.Rpc.Workload[0].Method = "eth_unknown" },
+			want:   []string{"rpc.workload: unsupported workload method: eth_unknown"},
+		},
+		{
+			name: "rpc mode with rates",
+			modify: func(s *tooltypes.Scenario) {
+				s.Rpc.Mode = &tooltypes.LoadTestModeParameters{Mode: tooltypes.StressMode, BaseRate: 10, PeakRate: 20, Step: 10, StepDuration: 5}
+			},
+			want: []string{"rpc.mode: cannot be combined with explicit rates"},
+		},
+		{
+			name: "rpc mode",
+			modify: func(s *tooltypes.Scenario) {
+				s.Rpc.Rates, s.Rpc.Durations = nil, nil
+				s.Rpc.Mode = &tooltypes.LoadTestModeParameters{Mode: "burst"}
+			},
+			want: []string{"rpc.mode: "},
+		},
+		{
+			name:   "rpc durations count",
+			modify: func(s *tooltypes.Scenario) { s.Rpc.Durations = []int{5} },
+			want:   []string{"rpc.durations: 1 durations given for 2 rates"},
+		},
+		{
+			name: "rpc rates and durations",
+			modify: func(s *tooltypes.Scenario) {
+				s.Rpc.Rates = []int{10, 0}
+				s.Rpc.Durations = []int{-1, 5}
+			},
+			want: []string{"rpc.rates[1]: must be positive", "rpc.durations[0]: must be positive"},
+		},
+		{
+			name:   "rpc durations without rates",
+			modify: func(s *tooltypes.Scenario) { s.Rpc.Rates = nil },
+			want:   []string{"rpc.durations: given without rates"},
+		},
+		{
+			name:   "rpc block range",
+			modify: func(s *tooltypes.Scenario) { s.Rpc.BlockRange = "yesterday" },
+			want:   []string{"rpc.block_range: "},
+		},
+		{
+			name:   "rpc deep output",
+			modify: func(s *tooltypes.Scenario) { s.Rpc.DeepOutput = []tooltypes.DeepOutput{tooltypes.RawDeepOutput, "csv"} },
+			want:   []string{`rpc.deep_output[1]: unknown deep output "csv"`},
+		},
+		{
+			name: "rpc replay",
+			modify: func(s *tooltypes.Scenario) {
+				s.Rpc.Replay = "load_test.json"
+				s.Rpc.SaveTest = "saved.json"
+				s.Rpc.CapacitySearch = &tooltypes.CapacitySearchParameters{}
+			},
+			want: []string{"rpc.replay: cannot be combined with a capacity search", "rpc.replay: cannot be combined with save_test"},
+		},
+		{
+			name: "rpc nodes mode and repeats",
+			modify: func(s *tooltypes.Scenario) {
+				s.Rpc.NodesMode = "parallel"
+				s.Rpc.Repeats = -1
+			},
+			want: []string{`rpc.nodes_mode: unknown nodes mode "parallel"`, "rpc.repeats: must not be negative"},
+		},
+		{
+			name: "rpc verify",
+			modify: func(s *tooltypes.Scenario) {
+				s.Rpc.Verify = &tooltypes.VerificationParameters{MaxSamples: -1}
+				s.Rpc.CapacitySearch = &tooltypes.CapacitySearchParameters{}
+			},
+			want: []string{"rpc.verify: cannot be combined with a capacity search", "rpc.verify.max_samples: must not be negative"},
+		},
+		{
+			name:   "rpc harvest blocks",
+			modify: func(s *tooltypes.Scenario) { s.Rpc.HarvestBlocks = -1 },
+			want:   []string{"rpc.harvest_blocks: must not be negative"},
+		},
+		{
+			name: "rpc capacity search",
+			modify: func(s *tooltypes.Scenario) {
+				s.Rpc.CapacitySearch = &tooltypes.CapacitySearchParameters{
+					StartRate: 100,
+					MaxRate:   10,
+					Precision: -1,
+					SLO:       tooltypes.SLO{MinSuccess: 1.5, MaxP99: -1},
+				}
+			},
+			want: []string{
+				"rpc.capacity_search: rates and durations must not be negative",
+				"rpc.capacity_search.max_rate: must not be lower than the start rate",
+				"rpc.capacity_search.slo.min_success: must be between 0 and 1",
+				"rpc.capacity_search.slo.max_p99: must not be negative",
+			},
+		},
+
+		{name: "tx type", modify: func(s *tooltypes.Scenario) { s.Tx.Type = "nft" }, want: []string{"tx.type: "}},
+		{name: "tx fee mode", modify: func(s *tooltypes.Scenario) { s.Tx.FeeMode = "cheap" }, want: []string{"tx.fee_mode: "}},
+		{name: "tx no blob", modify: func(s *tooltypes.Scenario) { s.Tx.BlobsPerTx = 0 }, want: []string{"tx.blobs_per_tx: must be between 1 and"}},
+		{
+			name:   "tx too many blobs",
+			modify: func(s *tooltypes.Scenario) { s.Tx.BlobsPerTx = tooltypes.MaxBlobsPerTx + 1 },
+			want:   []string{"tx.blobs_per_tx: must be between 1 and"},
+		},
+		{name: "tx gas", modify: func(s *tooltypes.Scenario) { s.Tx.GasPerTx = 100000 }, want: []string{"tx.gas_per_tx: is only used by stress workloads"}},
+		{
+			name: "tx stress gas",
+			modify: func(s *tooltypes.Scenario) {
+				s.Tx.Type = "sstore"
+				s.Tx.GasPerTx = 100000
+			},
+		},
+		{
+			name: "tx counts",
+			modify: func(s *tooltypes.Scenario) {
+				s.Tx.Accounts, s.Tx.Transactions, s.Tx.BatchSize = 0, -1, 0
+			},
+			want: []string{"tx.accounts: must be positive", "tx.transactions: must be positive", "tx.batch_size: must be positive"},
+		},
+		{
+			name:   "tx contract of another type",
+			modify: func(s *tooltypes.Scenario) { s.Tx.Contract = &tooltypes.ContractCallSpec{} },
+			want:   []string{"tx.contract: is only used by contract transactions"},
+		},
+		{
+			name:   "tx contract missing",
+			modify: func(s *tooltypes.Scenario) { s.Tx.Type = "contract" },
+			want:   []string{"tx.contract: is required by contract transactions"},
+		},
+		{
+			name: "tx contract",
+			modify: func(s *tooltypes.Scenario) {
+				s.Tx.Type = "contract"
+				s.Tx.Contract = &tooltypes.ContractCallSpec{Address: "0x12", ConstructorArgs: []string{"1"}}
+			},
+			want: []string{
+				"tx.contract.abi: is required",
+				"tx.contract.method: is required",
+				"tx.contract.address: invalid address 0x12",
+				"tx.contract.constructor_args: are only used to deploy the contract from its bin",
+			},
+		},
+		{
+			name: "tx contract bin and address",
+			modify: func(s *tooltypes.Scenario) {
+				s.Tx.Type = "contract"
+				s.Tx.Contract = &tooltypes.ContractCallSpec{
+					ABI:     "contract.abi",
+					Method:  "transfer",
+					Bin:     "contract.bin",
+					Address: "0x1111111111111111111111111111111111111111",
+				}
+			},
+			want: []string{"tx.contract: needs either a bin or an address"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := validScenario()
+			tt.modify(s)
+			errs := Validate(s)
+
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Validate = %v, want %d errors %q", errs, len(tt.want), tt.want)
+			}
+			for i, err := range errs {
+				if !strings.HasPrefix(err.Error(), tt.want[i]) {
+					t.Errorf("error %d = %q, want %q", i, err, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/olekukonko/tablewriter"
@@ -21,6 +22,7 @@ import (
 type TxStats struct {
 	TxHash string
 	Block  uint64
+	Status uint64
 }
 
 type BlockInfo struct {
@@ -30,11 +32,28 @@ type BlockInfo struct {
 	GasUsed        uint64
 	GasLimit       uint64
 	GasUtilization float64
+	BlockTime      uint64 // seconds since the parent block
+
+	// Blob data of post-Cancun blocks
+	NumBlobs      int
+	BlobGasUsed   uint64
+	ExcessBlobGas uint64
+}
+
+// DelegationStats are the outcome of the EIP-7702 authorizations of set code
+// transactions, and of the calls to the delegated accounts
+type DelegationStats struct {
+	Authorizations  int `json:"authorizations"`
+	Delegated       int `json:"delegated"`
+	Calls           int `json:"calls"`
+	SuccessfulCalls int `json:"successful_calls"`
 }
 
 type CollectorData struct {
-	TPS       float64
-	BlockInfo map[uint64]*BlockInfo
+	TPS         float64
+	MinedTxs    int
+	BlockInfo   map[uint64]*BlockInfo
+	Delegations *DelegationStats
 }
 
 func GatherTransactionReceipts(ethclient *ethclient.Client, txs []*types.Transaction, batchSize int) ([]*TxStats, error) {
@@ -64,7 +83,7 @@ func GatherTransactionReceipts(ethclient *ethclient.Client, txs []*types.Transac
 					errorsChan <- err
 					continue
 				}
-				txStatsChan <- &TxStats{TxHash: tx.Hash().Hex(), Block: receipt.BlockNumber.Uint64()}
+				txStatsChan <- &TxStats{TxHash: tx.Hash().Hex(), Block: receipt.BlockNumber.Uint64(), Status: receipt.Status}
 				bar.Add(1)
 			}
 		}(batch)
@@ -119,15 +138,31 @@ func FetchBlockInfo(ethclient *ethclient.Client, stats []*TxStats) (map[uint64]*
 				errorsChan <- err
 				return
 			}
+			parent, err := ethclient.HeaderByHash(context.Background(), blockInfo.ParentHash())
+			if err != nil {
+				errorsChan <- err
+				return
+			}
 			gasUtilization := float64(blockInfo.GasUsed()) / float64(blockInfo.GasLimit()) * 100
-			blockInfoChan <- &BlockInfo{
+			info := &BlockInfo{
 				BlockNum:       blockNum,
 				CreatedAt:      blockInfo.Time(),
 				NumTxs:         len(blockInfo.Transactions()),
 				GasUsed:        blockInfo.GasUsed(),
 				GasLimit:       blockInfo.GasLimit(),
 				GasUtilization: gasUtilization,
+				BlockTime:      blockInfo.Time() - parent.Time,
+			}
+			for _, tx := range blockInfo.Transactions() {
+				info.NumBlobs += len(tx.BlobHashes())
+			}
+			if blobGasUsed := blockInfo.BlobGasUsed(); blobGasUsed != nil {
+				info.BlobGasUsed = *blobGasUsed
+			}
+			if excessBlobGas := blockInfo.ExcessBlobGas(); excessBlobGas != nil {
+				info.ExcessBlobGas = *excessBlobGas
 			}
+			blockInfoChan <- info
 			bar.Add(1)
 		}(block)
 	}
@@ -159,6 +194,59 @@ func FetchBlockInfo(ethclient *ethclient.Client, stats []*TxStats) (map[uint64]*
 	return blocksMap, nil
 }
 
+// FetchDelegationStats checks which authorizations of the set code transactions
+// left their authority delegated, and how many calls to the delegated accounts
+// succeeded. It returns nil when no transaction is a set code transaction.
+func FetchDelegationStats(ethclient *ethclient.Client, txs []*types.Transaction, stats []*TxStats) (*DelegationStats, error) {
+	delegations := make(map[common.Address]common.Address)
+	authorizations := 0
+	for _, tx := range txs {
+		for _, auth := range tx.SetCodeAuthorizations() {
+			authorizations++
+			authority, err := auth.Authority()
+			if err != nil {
+				// An invalid authorization is skipped by the chain too
+				continue
+			}
+			delegations[authority] = auth.Address
+		}
+	}
+	if authorizations == 0 {
+		return nil, nil
+	}
+
+	log.Info().Msg("Checking delegations...")
+	data := &DelegationStats{Authorizations: authorizations}
+	for authority, address := range delegations {
+		code, err := ethclient.CodeAt(context.Background(), authority, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get code of %s: %v", authority.Hex(), err)
+		}
+		if delegate, ok := types.ParseDelegation(code); ok && delegate == address {
+			data.Delegated++
+		}
+	}
+
+	statuses := make(map[string]uint64, len(stats))
+	for _, s := range stats {
+		statuses[s.TxHash] = s.Status
+	}
+	for _, tx := range txs {
+		if tx.Type() == types.SetCodeTxType || tx.To() == nil {
+			continue
+		}
+		if _, ok := delegations[*tx.To()]; !ok {
+			continue
+		}
+		data.Calls++
+		if statuses[tx.Hash().Hex()] == types.ReceiptStatusSuccessful {
+			data.SuccessfulCalls++
+		}
+	}
+
+	return data, nil
+}
+
 func CalcTPS(stats []*TxStats, blockInfoMap map[uint64]*BlockInfo) float64 {
 	log.Info().Msg("🧮 Calculating TPS data 🧮")
 	totalTxs := 0
@@ -186,7 +274,7 @@ func CalcTPS(stats []*TxStats, blockInfoMap map[uint64]*BlockInfo) float64 {
 func PrintBlockData(blockInfoMap map[uint64]*BlockInfo) {
 	log.Info().Msg("Block utilization data:")
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Block #", "Gas Used [wei]", "Gas Limit [wei]", "Transactions", "Utilization"})
+	table.SetHeader([]string{"Block #", "Gas Used [wei]", "Gas Limit [wei]", "Transactions", "Utilization", "Block Time [s]", "Gas Rate [Mgas/s]"})
 
 	var blocks []uint64
 	for block := range blockInfoMap {
@@ -202,29 +290,121 @@ func PrintBlockData(blockInfoMap map[uint64]*BlockInfo) {
 			fmt.Sprintf("%d", info.GasLimit),
 			fmt.Sprintf("%d", info.NumTxs),
 			fmt.Sprintf("%.2f%%", info.GasUtilization),
+			fmt.Sprintf("%d", info.BlockTime),
+			formatGasRate(info.GasUsed, info.BlockTime),
 		})
 	}
 
 	table.Render()
 }
 
-func PrintFinalData(tps float64, blockInfoMap map[uint64]*BlockInfo) {
+// formatGasRate formats the gas processed per second of block time
+func formatGasRate(gasUsed uint64, blockTime uint64) string {
+	if blockTime == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", float64(gasUsed)/float64(blockTime)/1e6)
+}
+
+// PrintBlobData prints the blobs of each block, if any block carries blobs
+func PrintBlobData(blockInfoMap map[uint64]*BlockInfo) {
+	var blocks []uint64
+	totalBlobs := 0
+	for block, info := range blockInfoMap {
+		blocks = append(blocks, block)
+		totalBlobs += info.NumBlobs
+	}
+	if totalBlobs == 0 {
+		return
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+
+	log.Info().Msg("Block blob data:")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Block #", "Blobs", "Blob Gas Used", "Excess Blob Gas"})
+
+	for _, block := range blocks {
+		info := blockInfoMap[block]
+		table.Append([]string{
+			fmt.Sprintf("%d", info.BlockNum),
+			fmt.Sprintf("%d", info.NumBlobs),
+			fmt.Sprintf("%d", info.BlobGasUsed),
+			fmt.Sprintf("%d", info.ExcessBlobGas),
+		})
+	}
+
+	table.Render()
+	log.Info().Msgf("Avg. blobs per block: %.2f", float64(totalBlobs)/float64(len(blocks)))
+}
+
+// BlockSummary sums up the blocks of a transaction benchmark
+type BlockSummary struct {
+	Blocks         int
+	AvgUtilization float64
+	AvgBlockTime   float64
+	GasUsed        uint64
+	BlockTime      uint64
+}
+
+// GasRate formats the gas processed per second of block time
+func (s BlockSummary) GasRate() string {
+	return formatGasRate(s.GasUsed, s.BlockTime)
+}
+
+func SummarizeBlocks(blockInfoMap map[uint64]*BlockInfo) BlockSummary {
+	summary := BlockSummary{Blocks: len(blockInfoMap)}
+	if len(blockInfoMap) == 0 {
+		return summary
+	}
+
 	totalUtilization := 0.0
 	for _, info := range blockInfoMap {
 		totalUtilization += info.GasUtilization
+		summary.GasUsed += info.GasUsed
+		summary.BlockTime += info.BlockTime
 	}
-	avgUtilization := totalUtilization / float64(len(blockInfoMap))
+	summary.AvgUtilization = totalUtilization / float64(len(blockInfoMap))
+	summary.AvgBlockTime = float64(summary.BlockTime) / float64(len(blockInfoMap))
+	return summary
+}
+
+func PrintFinalData(tps float64, blockInfoMap map[uint64]*BlockInfo) {
+	summary := SummarizeBlocks(blockInfoMap)
 
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"TPS", "Blocks", "Avg. Utilization"})
+	table.SetHeader([]string{"TPS", "Blocks", "Avg. Utilization", "Avg. Block Time [s]", "Gas Rate [Mgas/s]"})
 	table.Append([]string{
 		fmt.Sprintf("%d", int(tps)),
-		fmt.Sprintf("%d", len(blockInfoMap)),
-		fmt.Sprintf("%.2f%%", avgUtilization),
+		fmt.Sprintf("%d", summary.Blocks),
+		fmt.Sprintf("%.2f%%", summary.AvgUtilization),
+		fmt.Sprintf("%.2f", summary.AvgBlockTime),
+		summary.GasRate(),
+	})
+	table.Render()
+}
+
+// PrintDelegationData prints the success rates of the delegations and of the delegated calls
+func PrintDelegationData(data *DelegationStats) {
+	log.Info().Msg("Delegation data:")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Authorizations", "Delegated", "Delegation Rate", "Delegated Calls", "Call Success Rate"})
+	table.Append([]string{
+		fmt.Sprintf("%d", data.Authorizations),
+		fmt.Sprintf("%d", data.Delegated),
+		formatRate(data.Delegated, data.Authorizations),
+		fmt.Sprintf("%d", data.Calls),
+		formatRate(data.SuccessfulCalls, data.Calls),
 	})
 	table.Render()
 }
 
+func formatRate(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", float64(n)/float64(total)*100)
+}
+
 func GenerateStats(ethclient *ethclient.Client, txHashes []*types.Transaction, batchSize int) (*CollectorData, error) {
 	if len(txHashes) == 0 {
 		log.Info().Msg("No stat data to display")
@@ -244,9 +424,18 @@ func GenerateStats(ethclient *ethclient.Client, txHashes []*types.Transaction, b
 	}
 
 	PrintBlockData(blockInfoMap)
+	PrintBlobData(blockInfoMap)
+
+	delegations, err := FetchDelegationStats(ethclient, txHashes, txStats)
+	if err != nil {
+		return nil, err
+	}
+	if delegations != nil {
+		PrintDelegationData(delegations)
+	}
 
 	avgTPS := CalcTPS(txStats, blockInfoMap)
 	PrintFinalData(avgTPS, blockInfoMap)
 
-	return &CollectorData{TPS: avgTPS, BlockInfo: blockInfoMap}, nil
+	return &CollectorData{TPS: avgTPS, MinedTxs: len(txStats), BlockInfo: blockInfoMap, Delegations: delegations}, nil
 }
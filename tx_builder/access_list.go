@@ -0,0 +1,58 @@
+package tx_builder
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/unifralabs/unifra-benchmark-tool/rpc_client"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// sharedAccessList returns the access list of the transactions of a builder,
+// from eth_createAccessList on two calls of the same shape: one from the
+// first account to the second, one from the third account to the fourth. It
+// keeps the addresses and storage keys accessed by both calls, since keys of
+// the sender or the receiver, such as their token balances, differ from one
+// transaction to the next and would only make the other transactions pay
+// for keys they do not access.
+func sharedAccessList(rpcClient *rpc_client.RpcClient, mnemonic string, call func(from, to common.Address) (ethereum.CallMsg, error)) (types.AccessList, error) {
+	lists := make([]types.AccessList, 2)
+	for i := range lists {
+		from, err := utils.DeriveAddressFromMnemonic(mnemonic, 2*i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive sender address: %v", err)
+		}
+		to, err := utils.DeriveAddressFromMnemonic(mnemonic, 2*i+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive receiver address: %v", err)
+		}
+
+		msg, err := call(*from, *to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct input: %v", err)
+		}
+		lists[i], err = rpcClient.CreateAccessList(msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create access list: %v", err)
+		}
+	}
+
+	shared := types.AccessList{}
+	for _, tuple := range lists[0] {
+		i := slices.IndexFunc(lists[1], func(other types.AccessTuple) bool { return other.Address == tuple.Address })
+		if i < 0 {
+			continue
+		}
+		keys := []common.Hash{}
+		for _, key := range tuple.StorageKeys {
+			if slices.Contains(lists[1][i].StorageKeys, key) {
+				keys = append(keys, key)
+			}
+		}
+		shared = append(shared, types.AccessTuple{Address: tuple.Address, StorageKeys: keys})
+	}
+	return shared, nil
+}
@@ -0,0 +1,197 @@
+package tx_builder
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+	"github.com/rs/zerolog/log"
+	"github.com/schollz/progressbar/v3"
+	"github.com/unifralabs/unifra-benchmark-tool/rpc_client"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+type BlobTxBuilder struct {
+	mnemonic      string
+	url           string
+	provider      *ethclient.Client
+	rpcClient     *rpc_client.RpcClient
+	gasEstimation *big.Int
+	defaultValue  *big.Int
+	blobsPerTx    int
+}
+
+func NewBlobTxBuilder(mnemonic, url string, blobsPerTx int) (*BlobTxBuilder, error) {
+	if blobsPerTx == 0 {
+		blobsPerTx = 1
+	}
+	if blobsPerTx < 0 || blobsPerTx > tooltypes.MaxBlobsPerTx {
+		return nil, fmt.Errorf("blobs per transaction must be between 1 and %d", tooltypes.MaxBlobsPerTx)
+	}
+
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+	}
+
+	rpcClient, err := rpc_client.NewRpcClientFromEthClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("error creating eth client: %s", err)
+	}
+
+	return &BlobTxBuilder{
+		mnemonic:      mnemonic,
+		url:           url,
+		provider:      client,
+		rpcClient:     rpcClient,
+		gasEstimation: big.NewInt(0),
+		defaultValue:  big.NewInt(0),
+		blobsPerTx:    blobsPerTx,
+	}, nil
+}
+
+func (e *BlobTxBuilder) Initialize() error {
+	header, err := e.provider.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest header: %v", err)
+	}
+	if header.ExcessBlobGas == nil {
+		return fmt.Errorf("chain does not support blob transactions")
+	}
+	return nil
+}
+
+func (e *BlobTxBuilder) EstimateGasForBaseTx() (*big.Int, error) {
+	from, err := utils.DeriveAddressFromMnemonic(e.mnemonic, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive 'from' address: %v", err)
+	}
+	to, err := utils.DeriveAddressFromMnemonic(e.mnemonic, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive 'to' address: %v", err)
+	}
+
+	// The blobs are paid with blob gas, the execution gas is that of an empty transfer
+	gasLimit, err := e.provider.EstimateGas(context.Background(), ethereum.CallMsg{
+		From:  *from,
+		To:    to,
+		Value: e.defaultValue,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+
+	e.gasEstimation = new(big.Int).SetUint64(gasLimit)
+	return e.gasEstimation, nil
+}
+
+func (e *BlobTxBuilder) GetValue() *big.Int {
+	return e.defaultValue
+}
+
+// GetFees always returns dynamic fees, blob transactions being EIP-1559
+// transactions, with a blob fee cap which covers a doubling of the blob base fee
+func (e *BlobTxBuilder) GetFees() (*tooltypes.TxFees, error) {
+	fees, err := e.rpcClient.SuggestFees(tooltypes.DynamicFees)
+	if err != nil {
+		return nil, err
+	}
+
+	blobBaseFee, err := e.provider.BlobBaseFee(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob base fee: %v", err)
+	}
+	fees.BlobFeeCap = new(big.Int).Mul(blobBaseFee, big.NewInt(2))
+	return fees, nil
+}
+
+func (e *BlobTxBuilder) GetBlobGas() uint64 {
+	return uint64(e.blobsPerTx) * params.BlobTxBlobGasPerBlob
+}
+
+func (e *BlobTxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount, numTx int, fees *tooltypes.TxFees) ([]*types.Transaction, error) {
+	chainID, err := e.provider.ChainID(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+
+	log.Info().Msgf("Chain ID: %s", chainID.String())
+	log.Info().Msgf("Fees (%s): %s, blob fee cap %s", fees.Mode, fees, fees.BlobFeeCap)
+
+	log.Info().Msgf("Constructing blob transactions with %d blobs each...", e.blobsPerTx)
+	bar := progressbar.Default(int64(numTx))
+
+	transactions := make([]*types.Transaction, numTx)
+
+	for i := 0; i < numTx; i++ {
+		senderIndex := i % len(accounts)
+		receiverIndex := (i + 1) % len(accounts)
+
+		sender := accounts[senderIndex]
+		receiver := accounts[receiverIndex]
+
+		sidecar, err := newRandomBlobSidecar(e.blobsPerTx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct blobs: %v", err)
+		}
+
+		tx := types.NewTx(&types.BlobTx{
+			ChainID:    uint256.MustFromBig(chainID),
+			Nonce:      sender.GetNonce(),
+			GasTipCap:  uint256.MustFromBig(fees.GasTipCap),
+			GasFeeCap:  uint256.MustFromBig(fees.GasFeeCap),
+			Gas:        e.gasEstimation.Uint64(),
+			To:         receiver.GetAddress(),
+			Value:      uint256.MustFromBig(e.defaultValue),
+			BlobFeeCap: uint256.MustFromBig(fees.BlobFeeCap),
+			BlobHashes: sidecar.BlobHashes(),
+			Sidecar:    sidecar,
+		})
+
+		transactions[i] = tx
+
+		sender.IncrNonce()
+		bar.Add(1)
+	}
+
+	log.Info().Msgf("Successfully constructed %d transactions", numTx)
+
+	return transactions, nil
+}
+
+// newRandomBlobSidecar generates random blobs with their KZG commitments and proofs
+func newRandomBlobSidecar(numBlobs int) (*types.BlobTxSidecar, error) {
+	sidecar := &types.BlobTxSidecar{}
+	for i := 0; i < numBlobs; i++ {
+		var blob kzg4844.Blob
+		if _, err := rand.Read(blob[:]); err != nil {
+			return nil, err
+		}
+		// Clear the top byte of each field element to keep it below the BLS modulus
+		for j := 0; j < len(blob); j += 32 {
+			blob[j] = 0
+		}
+
+		commitment, err := kzg4844.BlobToCommitment(&blob)
+		if err != nil {
+			return nil, err
+		}
+		proof, err := kzg4844.ComputeBlobProof(&blob, commitment)
+		if err != nil {
+			return nil, err
+		}
+
+		sidecar.Blobs = append(sidecar.Blobs, blob)
+		sidecar.Commitments = append(sidecar.Commitments, commitment)
+		sidecar.Proofs = append(sidecar.Proofs, proof)
+	}
+	return sidecar, nil
+}
@@ -0,0 +1,226 @@
+package tx_builder
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+var placeholderRegexp = regexp.MustCompile(`\{([a-z]+)(?::([^}]*))?\}`)
+
+// callContext is what the placeholders of argument templates refer to
+type callContext struct {
+	sender   common.Address
+	receiver common.Address
+	account  int
+	index    int
+	rng      *rand.Rand
+}
+
+// buildArgs builds the values of the ABI arguments from their templates
+func buildArgs(inputs abi.Arguments, templates []string, ctx *callContext) ([]interface{}, error) {
+	if len(templates) != len(inputs) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(inputs), len(templates))
+	}
+
+	values := make([]interface{}, len(inputs))
+	for i, input := range inputs {
+		value, err := buildArg(input.Type, strings.TrimSpace(templates[i]), ctx)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s %s): %v", i, input.Type, input.Name, err)
+		}
+		values[i] = value.Interface()
+	}
+	return values, nil
+}
+
+func buildArg(t abi.Type, template string, ctx *callContext) (reflect.Value, error) {
+	if t.T == abi.SliceTy || t.T == abi.ArrayTy {
+		if !strings.HasPrefix(template, "[") || !strings.HasSuffix(template, "]") {
+			return reflect.Value{}, fmt.Errorf("%s must be written as [a,b,...]", t)
+		}
+		elems := utils.SplitArgs(template[1 : len(template)-1])
+		if t.T == abi.ArrayTy && len(elems) != t.Size {
+			return reflect.Value{}, fmt.Errorf("%s needs %d elements, got %d", t, t.Size, len(elems))
+		}
+
+		var value reflect.Value
+		if t.T == abi.SliceTy {
+			value = reflect.MakeSlice(t.GetType(), len(elems), len(elems))
+		} else {
+			value = reflect.New(t.GetType()).Elem()
+		}
+		for i, elem := range elems {
+			elemValue, err := buildArg(*t.Elem, elem, ctx)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value.Index(i).Set(elemValue)
+		}
+		return value, nil
+	}
+
+	text, err := expandPlaceholders(t, template, ctx)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return parseLiteral(t, text)
+}
+
+// expandPlaceholders replaces the placeholders of a template by their text
+func expandPlaceholders(t abi.Type, template string, ctx *callContext) (string, error) {
+	var err error
+	text := placeholderRegexp.ReplaceAllStringFunc(template, func(placeholder string) string {
+		match := placeholderRegexp.FindStringSubmatch(placeholder)
+		name, param := match[1], match[2]
+
+		switch {
+		case name == "sender":
+			return ctx.sender.Hex()
+		case name == "receiver":
+			return ctx.receiver.Hex()
+		case name == "account":
+			return strconv.Itoa(ctx.account)
+		case name == "index":
+			return strconv.Itoa(ctx.index)
+		case name == "random" && param == "":
+			var value string
+			value, err = randomLiteral(t, ctx.rng)
+			return value
+		case name == "random":
+			var value string
+			value, err = randomInRange(param, ctx.rng)
+			return value
+		default:
+			err = fmt.Errorf("unknown placeholder %s", placeholder)
+			return placeholder
+		}
+	})
+	return text, err
+}
+
+// randomLiteral returns the text of a random value of an ABI type
+func randomLiteral(t abi.Type, rng *rand.Rand) (string, error) {
+	randomBytes := func(n int) []byte {
+		b := make([]byte, n)
+		rng.Read(b)
+		return b
+	}
+
+	switch t.T {
+	case abi.UintTy:
+		return new(big.Int).SetBytes(randomBytes(t.Size / 8)).String(), nil
+	case abi.IntTy:
+		value := new(big.Int).SetBytes(randomBytes(t.Size / 8))
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1)))
+		return value.String(), nil
+	case abi.BoolTy:
+		return strconv.FormatBool(rng.Intn(2) == 1), nil
+	case abi.AddressTy:
+		return common.BytesToAddress(randomBytes(common.AddressLength)).Hex(), nil
+	case abi.FixedBytesTy:
+		return hexutil.Encode(randomBytes(t.Size)), nil
+	case abi.BytesTy, abi.StringTy:
+		return hexutil.Encode(randomBytes(32)), nil
+	default:
+		return "", fmt.Errorf("no random value of type %s", t)
+	}
+}
+
+// randomInRange returns a random integer of a MIN..MAX range, bounds included
+func randomInRange(bounds string, rng *rand.Rand) (string, error) {
+	parts := strings.SplitN(bounds, "..", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid random range %q, expected MIN..MAX", bounds)
+	}
+	min, ok := new(big.Int).SetString(strings.TrimSpace(parts[0]), 0)
+	if !ok {
+		return "", fmt.Errorf("invalid random range minimum %q", parts[0])
+	}
+	max, ok := new(big.Int).SetString(strings.TrimSpace(parts[1]), 0)
+	if !ok {
+		return "", fmt.Errorf("invalid random range maximum %q", parts[1])
+	}
+	if max.Cmp(min) < 0 {
+		return "", fmt.Errorf("random range maximum %s is below its minimum %s", max, min)
+	}
+
+	span := new(big.Int).Sub(max, min)
+	span.Add(span, big.NewInt(1))
+	value := new(big.Int).Rand(rng, span)
+	return value.Add(value, min).String(), nil
+}
+
+// parseLiteral parses the text of a value into the Go type that the ABI packs
+func parseLiteral(t abi.Type, text string) (reflect.Value, error) {
+	switch t.T {
+	case abi.UintTy, abi.IntTy:
+		value, ok := new(big.Int).SetString(text, 0)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid integer %q", text)
+		}
+		if !fitsInteger(t, value) {
+			return reflect.Value{}, fmt.Errorf("%s overflows %s", text, t)
+		}
+		if t.GetType() == reflect.TypeOf(value) {
+			return reflect.ValueOf(value), nil
+		}
+		native := reflect.New(t.GetType()).Elem()
+		if t.T == abi.UintTy {
+			native.SetUint(value.Uint64())
+		} else {
+			native.SetInt(value.Int64())
+		}
+		return native, nil
+	case abi.BoolTy:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bool %q", text)
+		}
+		return reflect.ValueOf(value), nil
+	case abi.AddressTy:
+		if !common.IsHexAddress(text) {
+			return reflect.Value{}, fmt.Errorf("invalid address %q", text)
+		}
+		return reflect.ValueOf(common.HexToAddress(text)), nil
+	case abi.StringTy:
+		return reflect.ValueOf(text), nil
+	case abi.BytesTy:
+		value, err := hexutil.Decode(text)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bytes %q: %v", text, err)
+		}
+		return reflect.ValueOf(value), nil
+	case abi.FixedBytesTy:
+		value, err := hexutil.Decode(text)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bytes %q: %v", text, err)
+		}
+		if len(value) != t.Size {
+			return reflect.Value{}, fmt.Errorf("%s needs %d bytes, got %d", t, t.Size, len(value))
+		}
+		fixed := reflect.New(t.GetType()).Elem()
+		reflect.Copy(fixed, reflect.ValueOf(value))
+		return fixed, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported argument type %s", t)
+	}
+}
+
+// fitsInteger checks that an integer is within the range of an ABI integer type
+func fitsInteger(t abi.Type, value *big.Int) bool {
+	if t.T == abi.UintTy {
+		return value.Sign() >= 0 && value.BitLen() <= t.Size
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+	return value.Cmp(new(big.Int).Neg(limit)) >= 0 && value.Cmp(limit) < 0
+}
@@ -0,0 +1,253 @@
+package tx_builder
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testABI = `[{"type":"function","name":"call","inputs":[
+	{"name":"small","type":"uint8"},
+	{"name":"signed","type":"int16"},
+	{"name":"amount","type":"uint256"},
+	{"name":"delta","type":"int256"},
+	{"name":"tag","type":"bytes4"},
+	{"name":"pair","type":"address[2]"},
+	{"name":"flag","type":"bool"}
+]}]`
+
+func testInputs(t *testing.T) abi.Arguments {
+	t.Helper()
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	if err != nil {
+		t.Fatalf("parse ABI: %v", err)
+	}
+	return parsed.Methods["call"].Inputs
+}
+
+func testCallContext() *callContext {
+	return &callContext{
+		sender:   common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		receiver: common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		account:  3,
+		index:    7,
+		rng:      rand.New(rand.NewSource(1)),
+	}
+}
+
+func abiType(t *testing.T, name string) abi.Type {
+	t.Helper()
+	typ, err := abi.NewType(name, "", nil)
+	if err != nil {
+		t.Fatalf("new type %s: %v", name, err)
+	}
+	return typ
+}
+
+func TestBuildArgs(t *testing.T) {
+	inputs := testInputs(t)
+	sender := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	receiver := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	tests := []struct {
+		name      string
+		templates []string
+		want      []interface{}
+		wantErr   string
+	}{
+		{
+			name:      "literals and placeholders",
+			templates: []string{"{account}", "-{index}", "{index}000", "-5", "0x01020304", "[{sender},{receiver}]", "true"},
+			want:      []interface{}{uint8(3), int16(-7), big.NewInt(7000), big.NewInt(-5), [4]byte{1, 2, 3, 4}, [2]common.Address{sender, receiver}, true},
+		},
+		{
+			name:      "argument count",
+			templates: []string{"1"},
+			wantErr:   "expected 7 arguments, got 1",
+		},
+		{
+			name:      "fixed array too short",
+			templates: []string{"1", "1", "1", "1", "0x01020304", "[{sender}]", "true"},
+			wantErr:   "address[2] needs 2 elements, got 1",
+		},
+		{
+			name:      "fixed array too long",
+			templates: []string{"1", "1", "1", "1", "0x01020304", "[{sender},{sender},{sender}]", "true"},
+			wantErr:   "address[2] needs 2 elements, got 3",
+		},
+		{
+			name:      "array without brackets",
+			templates: []string{"1", "1", "1", "1", "0x01020304", "{sender}", "true"},
+			wantErr:   "must be written as [a,b,...]",
+		},
+		{
+			name:      "unknown placeholder",
+			templates: []string{"{nonce}", "1", "1", "1", "0x01020304", "[{sender},{sender}]", "true"},
+			wantErr:   "unknown placeholder {nonce}",
+		},
+		{
+			name:      "overflow",
+			templates: []string{"256", "1", "1", "1", "0x01020304", "[{sender},{sender}]", "true"},
+			wantErr:   "argument 0 (uint8 small): 256 overflows uint8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildArgs(inputs, tt.templates, testCallContext())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("buildArgs error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildArgs error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildArgs = %v, want %v", got, tt.want)
+			}
+			if _, err := inputs.Pack(got...); err != nil {
+				t.Errorf("pack the arguments: %v", err)
+			}
+		})
+	}
+}
+
+func TestBuildArgsRandom(t *testing.T) {
+	inputs := testInputs(t)
+	templates := []string{"{random}", "{random}", "{random:1..1000}", "{random:-10..10}", "{random}", "[{random},{random}]", "{random}"}
+
+	ctx := testCallContext()
+	for i := 0; i < 200; i++ {
+		values, err := buildArgs(inputs, templates, ctx)
+		if err != nil {
+			t.Fatalf("buildArgs: %v", err)
+		}
+		if amount := values[2].(*big.Int); amount.Cmp(big.NewInt(1)) < 0 || amount.Cmp(big.NewInt(1000)) > 0 {
+			t.Errorf("random amount %s is not within 1..1000", amount)
+		}
+		if delta := values[3].(*big.Int); delta.Cmp(big.NewInt(-10)) < 0 || delta.Cmp(big.NewInt(10)) > 0 {
+			t.Errorf("random delta %s is not within -10..10", delta)
+		}
+		if _, err := inputs.Pack(values...); err != nil {
+			t.Fatalf("pack the random arguments: %v", err)
+		}
+	}
+}
+
+func TestRandomInRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		bounds  string
+		min     int64
+		max     int64
+		wantErr string
+	}{
+		{name: "positive", bounds: "1..1000", min: 1, max: 1000},
+		{name: "signed", bounds: "-100..-50", min: -100, max: -50},
+		{name: "single value", bounds: "-5..-5", min: -5, max: -5},
+		{name: "hex and spaces", bounds: " 0x10 .. 0x20 ", min: 16, max: 32},
+		{name: "missing maximum", bounds: "1", wantErr: "expected MIN..MAX"},
+		{name: "invalid minimum", bounds: "a..10", wantErr: "invalid random range minimum"},
+		{name: "invalid maximum", bounds: "1..b", wantErr: "invalid random range maximum"},
+		{name: "reversed", bounds: "10..1", wantErr: "below its minimum"},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got, err := randomInRange(tt.bounds, rng)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("randomInRange(%q) error = %v, want %q", tt.bounds, err, tt.wantErr)
+					}
+					return
+				}
+				value, ok := new(big.Int).SetString(got, 10)
+				if err != nil || !ok || value.Cmp(big.NewInt(tt.min)) < 0 || value.Cmp(big.NewInt(tt.max)) > 0 {
+					t.Fatalf("randomInRange(%q) = %q, %v, want within %d..%d", tt.bounds, got, err, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRandomLiteralSigned(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, name := range []string{"int8", "int16", "int64", "int256"} {
+		typ := abiType(t, name)
+		var negative, positive bool
+		for i := 0; i < 200; i++ {
+			text, err := randomLiteral(typ, rng)
+			if err != nil {
+				t.Fatalf("randomLiteral(%s): %v", name, err)
+			}
+			value, _ := new(big.Int).SetString(text, 10)
+			if !fitsInteger(typ, value) {
+				t.Fatalf("randomLiteral(%s) = %s overflows", name, text)
+			}
+			negative = negative || value.Sign() < 0
+			positive = positive || value.Sign() > 0
+		}
+		if !negative || !positive {
+			t.Errorf("randomLiteral(%s) drew negative %t and positive %t values, want both", name, negative, positive)
+		}
+	}
+}
+
+func TestParseLiteral(t *testing.T) {
+	tests := []struct {
+		typ     string
+		text    string
+		want    interface{}
+		wantErr string
+	}{
+		{typ: "uint8", text: "255", want: uint8(255)},
+		{typ: "uint8", text: "256", wantErr: "256 overflows uint8"},
+		{typ: "uint8", text: "-1", wantErr: "-1 overflows uint8"},
+		{typ: "int8", text: "-128", want: int8(-128)},
+		{typ: "int8", text: "127", want: int8(127)},
+		{typ: "int8", text: "128", wantErr: "128 overflows int8"},
+		{typ: "int8", text: "-129", wantErr: "-129 overflows int8"},
+		{typ: "uint32", text: "0xffffffff", want: uint32(0xffffffff)},
+		{typ: "int64", text: "-9223372036854775808", want: int64(-9223372036854775808)},
+		{typ: "uint64", text: "18446744073709551615", want: uint64(18446744073709551615)},
+		{typ: "uint24", text: "16777215", want: big.NewInt(16777215)},
+		{typ: "uint24", text: "16777216", wantErr: "overflows uint24"},
+		{typ: "int256", text: "-1", want: big.NewInt(-1)},
+		{typ: "uint256", text: "1e3", wantErr: "invalid integer"},
+		{typ: "bytes4", text: "0x01020304", want: [4]byte{1, 2, 3, 4}},
+		{typ: "bytes4", text: "0x010203", wantErr: "bytes4 needs 4 bytes, got 3"},
+		{typ: "bytes32", text: "0x" + strings.Repeat("ab", 32), want: [32]byte(common.HexToHash("0x" + strings.Repeat("ab", 32)))},
+		{typ: "bytes", text: "0x0102", want: []byte{1, 2}},
+		{typ: "bytes", text: "0102", wantErr: "invalid bytes"},
+		{typ: "bool", text: "yes", wantErr: "invalid bool"},
+		{typ: "address", text: "0x12", wantErr: "invalid address"},
+		{typ: "string", text: "hello", want: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+tt.text, func(t *testing.T) {
+			got, err := parseLiteral(abiType(t, tt.typ), tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseLiteral error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseLiteral error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.want) {
+				t.Errorf("parseLiteral = %#v, want %#v", got.Interface(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package tx_builder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rs/zerolog/log"
+	"github.com/schollz/progressbar/v3"
+	"github.com/unifralabs/unifra-benchmark-tool/rpc_client"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// ContractTxBuilder calls a method of any contract, described by its ABI,
+// with arguments generated from templates. It deploys the contract unless it
+// is given an existing address.
+type ContractTxBuilder struct {
+	mnemonic        string
+	url             string
+	provider        *ethclient.Client
+	rpcClient       *rpc_client.RpcClient
+	gasEstimation   *big.Int
+	feeMode         tooltypes.FeeMode
+	accessList      types.AccessList
+	spec            tooltypes.ContractCallSpec
+	contractAbi     abi.ABI
+	method          abi.Method
+	value           *big.Int
+	rng             *rand.Rand
+	contractAddress *common.Address
+	baseDeployer    *bind.TransactOpts
+}
+
+func NewContractTxBuilder(mnemonic, url string, feeMode tooltypes.FeeMode, spec *tooltypes.ContractCallSpec) (*ContractTxBuilder, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("contract transactions require a contract ABI and method")
+	}
+	if (spec.Bin == "") == (spec.Address == "") {
+		return nil, fmt.Errorf("contract transactions require either a contract bytecode or address")
+	}
+	if spec.Address != "" && !common.IsHexAddress(spec.Address) {
+		return nil, fmt.Errorf("invalid contract address: %s", spec.Address)
+	}
+
+	abiFile, err := os.Open(spec.ABI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open contract ABI: %v", err)
+	}
+	defer abiFile.Close()
+
+	contractAbi, err := abi.JSON(abiFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse contract ABI: %v", err)
+	}
+
+	method, ok := contractAbi.Methods[spec.Method]
+	if !ok {
+		return nil, fmt.Errorf("method %s not found in contract ABI", spec.Method)
+	}
+	if len(spec.Args) != len(method.Inputs) {
+		return nil, fmt.Errorf("method %s expects %d arguments, got %d", method.Sig, len(method.Inputs), len(spec.Args))
+	}
+
+	value := big.NewInt(0)
+	if spec.Value != "" {
+		if _, ok := value.SetString(spec.Value, 0); !ok || value.Sign() < 0 {
+			return nil, fmt.Errorf("invalid call value: %s", spec.Value)
+		}
+	}
+
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+	}
+
+	rpcClient, err := rpc_client.NewRpcClientFromEthClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("error creating eth client: %s", err)
+	}
+
+	return &ContractTxBuilder{
+		mnemonic:      mnemonic,
+		url:           url,
+		provider:      client,
+		rpcClient:     rpcClient,
+		gasEstimation: big.NewInt(0),
+		feeMode:       feeMode,
+		spec:          *spec,
+		contractAbi:   contractAbi,
+		method:        method,
+		value:         value,
+		rng:           rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+func (e *ContractTxBuilder) Initialize() error {
+	_, privateKey, err := utils.DerivePrivateKeyFromMnemonic(e.mnemonic, 0)
+	if err != nil {
+		return fmt.Errorf("failed to derive private key: %v", err)
+	}
+
+	chainID, err := e.provider.ChainID(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %v", err)
+	}
+
+	fees, err := e.GetFees()
+	if err != nil {
+		return err
+	}
+
+	e.baseDeployer, err = bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to create transactor: %v", err)
+	}
+	utils.ApplyFees(e.baseDeployer, fees)
+
+	if e.spec.Address != "" {
+		address := common.HexToAddress(e.spec.Address)
+		code, err := e.provider.CodeAt(context.Background(), address, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get contract code: %v", err)
+		}
+		if len(code) == 0 {
+			return fmt.Errorf("no contract deployed at %s", address.Hex())
+		}
+		e.contractAddress = &address
+		log.Info().Msgf("Using contract at address: %s", address.Hex())
+		return nil
+	}
+
+	bin, err := os.ReadFile(e.spec.Bin)
+	if err != nil {
+		return fmt.Errorf("failed to read contract bytecode: %v", err)
+	}
+	bytecode, err := hexutil.Decode(ensureHexPrefix(strings.TrimSpace(string(bin))))
+	if err != nil {
+		return fmt.Errorf("failed to decode contract bytecode: %v", err)
+	}
+
+	constructorArgs, err := buildArgs(e.contractAbi.Constructor.Inputs, e.spec.ConstructorArgs, e.deployerContext())
+	if err != nil {
+		return fmt.Errorf("failed to build constructor arguments: %v", err)
+	}
+
+	// Deploy the contract
+	address, tx, _, err := bind.DeployContract(e.baseDeployer, e.contractAbi, bytecode, e.provider, constructorArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to deploy contract: %v", err)
+	}
+
+	_, err = bind.WaitDeployed(context.Background(), e.provider, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for contract deployment: %v", err)
+	}
+
+	e.contractAddress = &address
+	log.Info().Msgf("Contract deployed at address: %s", address.Hex())
+
+	return nil
+}
+
+// deployerContext is the context of the calls sent by the admin account
+func (e *ContractTxBuilder) deployerContext() *callContext {
+	return &callContext{
+		sender:   e.baseDeployer.From,
+		receiver: e.baseDeployer.From,
+		rng:      e.rng,
+	}
+}
+
+func ensureHexPrefix(s string) string {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return s
+	}
+	return "0x" + s
+}
+
+// constructContractCall packs the call of the method for a context
+func (e *ContractTxBuilder) constructContractCall(ctx *callContext) ([]byte, error) {
+	args, err := buildArgs(e.method.Inputs, e.spec.Args, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return e.contractAbi.Pack(e.method.Name, args...)
+}
+
+func (e *ContractTxBuilder) EstimateGasForBaseTx() (*big.Int, error) {
+	if e.contractAddress == nil {
+		return nil, fmt.Errorf("runtime not initialized")
+	}
+
+	input, err := e.constructContractCall(e.deployerContext())
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct input: %v", err)
+	}
+
+	msg := ethereum.CallMsg{
+		From:  e.baseDeployer.From,
+		To:    e.contractAddress,
+		Value: e.value,
+		Data:  input,
+	}
+	if e.feeMode == tooltypes.AccessListFees {
+		e.accessList, err = sharedAccessList(e.rpcClient, e.mnemonic, func(from, to common.Address) (ethereum.CallMsg, error) {
+			input, err := e.constructContractCall(&callContext{sender: from, receiver: to, rng: e.rng})
+			return ethereum.CallMsg{From: from, To: e.contractAddress, Value: e.value, Data: input}, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		msg.AccessList = e.accessList
+	}
+
+	gasEstimation, err := e.provider.EstimateGas(context.Background(), msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+	log.Info().Msgf("%s gasEstimation: %d", e.method.Name, gasEstimation)
+
+	// Leave room for the random arguments, which can take other code paths
+	e.gasEstimation = big.NewInt(int64(gasEstimation * 2))
+	return e.gasEstimation, nil
+}
+
+func (e *ContractTxBuilder) GetValue() *big.Int {
+	return e.value
+}
+
+func (e *ContractTxBuilder) GetFees() (*tooltypes.TxFees, error) {
+	return e.rpcClient.SuggestFees(e.feeMode)
+}
+
+func (e *ContractTxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount, numTx int, fees *tooltypes.TxFees) ([]*types.Transaction, error) {
+	if e.contractAddress == nil {
+		return nil, fmt.Errorf("runtime not initialized")
+	}
+
+	chainID, err := e.provider.ChainID(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+
+	log.Info().Msgf("Chain ID: %s", chainID.String())
+	log.Info().Msgf("Fees (%s): %s", fees.Mode, fees)
+
+	log.Info().Msgf("Constructing %s transactions...", e.method.Sig)
+	bar := progressbar.Default(int64(numTx))
+
+	transactions := make([]*types.Transaction, numTx)
+
+	for i := 0; i < numTx; i++ {
+		senderIndex := i % len(accounts)
+		receiverIndex := (i + 1) % len(accounts)
+
+		sender := accounts[senderIndex]
+		receiver := accounts[receiverIndex]
+
+		input, err := e.constructContractCall(&callContext{
+			sender:   sender.GetAddress(),
+			receiver: receiver.GetAddress(),
+			account:  senderIndex,
+			index:    i,
+			rng:      e.rng,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct input: %v", err)
+		}
+
+		tx := utils.NewFeeTransaction(chainID, fees, sender.GetNonce(), *e.contractAddress, e.value, e.gasEstimation.Uint64(), input, e.accessList)
+
+		transactions[i] = tx
+
+		sender.IncrNonce()
+		bar.Add(1)
+	}
+
+	log.Info().Msgf("Successfully constructed %d transactions", numTx)
+
+	return transactions, nil
+}
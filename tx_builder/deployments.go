@@ -0,0 +1,77 @@
+package tx_builder
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+	"github.com/unifralabs/unifra-benchmark-tool/rpc_client"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// findDeployedContract returns the address of the contract that the deployer
+// recorded under name, if it was deployed from the same creation code and its
+// code is still on chain. It returns nil when the contract must be deployed.
+func findDeployedContract(registry tooltypes.ContractRegistry, rpcClient *rpc_client.RpcClient,
+	chainID *big.Int, deployer common.Address, name string, creationCode []byte) (*common.Address, error) {
+	if registry == nil {
+		return nil, nil
+	}
+
+	contract, err := registry.GetContract(chainID.String(), deployer.Hex(), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployed %s contract: %v", name, err)
+	}
+	if contract == nil {
+		return nil, nil
+	}
+	if contract.BinHash != crypto.Keccak256Hash(creationCode).Hex() {
+		log.Info().Msgf("Bytecode of the %s contract changed, redeploying", name)
+		return nil, nil
+	}
+
+	code, err := rpcClient.GetCode(contract.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code of %s contract: %v", name, err)
+	}
+	if code == "" || crypto.Keccak256Hash(common.FromHex(code)).Hex() != contract.CodeHash {
+		log.Info().Msgf("The %s contract is missing at %s, redeploying", name, contract.Address)
+		return nil, nil
+	}
+
+	address := common.HexToAddress(contract.Address)
+	return &address, nil
+}
+
+// recordDeployedContract records a newly deployed contract for later runs
+func recordDeployedContract(registry tooltypes.ContractRegistry, rpcClient *rpc_client.RpcClient,
+	chainID *big.Int, deployer common.Address, name string, creationCode []byte, address common.Address) error {
+	if registry == nil {
+		return nil
+	}
+
+	code, err := rpcClient.GetCode(address.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to get code of %s contract: %v", name, err)
+	}
+
+	err = registry.SaveContract(tooltypes.DeployedContract{
+		ChainID:  chainID.String(),
+		Deployer: deployer.Hex(),
+		Name:     name,
+		Address:  address.Hex(),
+		BinHash:  crypto.Keccak256Hash(creationCode).Hex(),
+		CodeHash: crypto.Keccak256Hash(common.FromHex(code)).Hex(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record deployed %s contract: %v", name, err)
+	}
+	return nil
+}
+
+// creationCode returns the creation code of a contract with its packed constructor arguments
+func creationCode(bin string, constructorArgs []byte) []byte {
+	return append(common.FromHex(bin), constructorArgs...)
+}
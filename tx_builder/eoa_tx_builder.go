@@ -6,6 +6,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
@@ -13,6 +14,7 @@ import (
 	"github.com/schollz/progressbar/v3"
 	"github.com/unifralabs/unifra-benchmark-tool/rpc_client"
 	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
 )
 
 type EOATxBuilder struct {
@@ -23,11 +25,13 @@ type EOATxBuilder struct {
 
 	gasEstimation *big.Int
 	gasPrice      *big.Int
+	feeMode       tooltypes.FeeMode
+	accessList    types.AccessList
 
 	defaultValue *big.Int
 }
 
-func NewEOATxBuilder(mnemonic, url string) (*EOATxBuilder, error) {
+func NewEOATxBuilder(mnemonic, url string, feeMode tooltypes.FeeMode) (*EOATxBuilder, error) {
 	client, err := ethclient.Dial(url)
 	if err != nil {
 		return nil, err
@@ -45,6 +49,7 @@ func NewEOATxBuilder(mnemonic, url string) (*EOATxBuilder, error) {
 		rpcClient:     rpcClient,
 		gasEstimation: big.NewInt(0),
 		gasPrice:      big.NewInt(0),
+		feeMode:       feeMode,
 		defaultValue:  big.NewInt(1e14), // 0.0001 ETH
 	}, nil
 }
@@ -77,11 +82,21 @@ func (e *EOATxBuilder) EstimateGasForBaseTx() (*big.Int, error) {
 	to := toAccount.Address
 
 	// Estimate gas for a simple value transfer
-	gasLimit, err := e.provider.EstimateGas(context.Background(), ethereum.CallMsg{
+	msg := ethereum.CallMsg{
 		From:  from,
 		To:    &to,
 		Value: e.defaultValue,
-	})
+	}
+	if e.feeMode == tooltypes.AccessListFees {
+		e.accessList, err = sharedAccessList(e.rpcClient, e.mnemonic, func(from, to common.Address) (ethereum.CallMsg, error) {
+			return ethereum.CallMsg{From: from, To: &to, Value: e.defaultValue}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		msg.AccessList = e.accessList
+	}
+	gasLimit, err := e.provider.EstimateGas(context.Background(), msg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to estimate gas: %w", err)
 	}
@@ -96,20 +111,19 @@ func (e *EOATxBuilder) GetValue() *big.Int {
 	return e.defaultValue
 }
 
-func (e *EOATxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount, numTx int) ([]*types.Transaction, error) {
+func (e *EOATxBuilder) GetFees() (*tooltypes.TxFees, error) {
+	return e.rpcClient.SuggestFees(e.feeMode)
+}
 
-	chainID, err := e.provider.NetworkID(context.Background())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get chain ID: %w", err)
-	}
+func (e *EOATxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount, numTx int, fees *tooltypes.TxFees) ([]*types.Transaction, error) {
 
-	gasPrice, err := e.rpcClient.GetGasPrice()
+	chainID, err := e.provider.ChainID(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
 	}
 
 	log.Info().Msgf("Chain ID: %s", chainID.String())
-	log.Info().Msgf("Avg. gas price: %s", gasPrice.String())
+	log.Info().Msgf("Fees (%s): %s", fees.Mode, fees)
 
 	log.Info().Msg("Constructing value transfer transactions...")
 	bar := progressbar.Default(int64(numTx))
@@ -123,7 +137,7 @@ func (e *EOATxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount
 		sender := accounts[senderIndex]
 		receiver := accounts[receiverIndex]
 
-		tx := types.NewTransaction(sender.GetNonce(), receiver.GetAddress(), e.defaultValue, e.gasEstimation.Uint64(), gasPrice, nil)
+		tx := utils.NewFeeTransaction(chainID, fees, sender.GetNonce(), receiver.GetAddress(), e.defaultValue, e.gasEstimation.Uint64(), nil, e.accessList)
 
 		transactions[i] = tx
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -28,18 +29,24 @@ type ERC20TxBuilder struct {
 	rpcClient     *rpc_client.RpcClient
 	gasEstimation *big.Int
 	gasPrice      *big.Int
+	feeMode       tooltypes.FeeMode
+	accessList    types.AccessList
 	defaultValue  *big.Int
 
 	defaultTransferValue *big.Int
 	totalSupply          *big.Int
+	txCount              int
 	coinName             string
 	coinSymbol           string
 	contractAddress      *common.Address
 	contract             *erc20.Erc20
 	baseDeployer         *bind.TransactOpts
+	contracts            tooltypes.ContractRegistry
 }
 
-func NewERC20TxBuilder(mnemonic, url string) (*ERC20TxBuilder, error) {
+// NewERC20TxBuilder creates the builder of the token transfers of a run of
+// txCount transactions
+func NewERC20TxBuilder(mnemonic, url string, feeMode tooltypes.FeeMode, contracts tooltypes.ContractRegistry, txCount int) (*ERC20TxBuilder, error) {
 	client, err := ethclient.Dial(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to the Ethereum client: %v", err)
@@ -57,11 +64,14 @@ func NewERC20TxBuilder(mnemonic, url string) (*ERC20TxBuilder, error) {
 		rpcClient:            rpcClient,
 		gasEstimation:        big.NewInt(0),
 		gasPrice:             big.NewInt(0),
+		feeMode:              feeMode,
 		defaultValue:         big.NewInt(0),
 		defaultTransferValue: big.NewInt(1),
 		totalSupply:          big.NewInt(500000000000),
+		txCount:              txCount,
 		coinName:             "Zex Coin",
 		coinSymbol:           "ZEX",
+		contracts:            contracts,
 	}, nil
 }
 
@@ -76,18 +86,54 @@ func (e *ERC20TxBuilder) Initialize() error {
 		return fmt.Errorf("failed to get chain ID: %v", err)
 	}
 
-	baseGasPrice, err := e.rpcClient.GetGasPrice()
+	fees, err := e.GetFees()
 	if err != nil {
-		return fmt.Errorf("failed to get gas price: %v", err)
+		return err
 	}
 
 	e.baseDeployer, err = bind.NewKeyedTransactorWithChainID(privateKey, chainID)
 	if err != nil {
 		return fmt.Errorf("failed to create transactor: %v", err)
 	}
-	e.baseDeployer.GasPrice = baseGasPrice
+	utils.ApplyFees(e.baseDeployer, fees)
 	e.baseDeployer.GasLimit = 2000000
 
+	constructorArgs, err := Erc20Abi.Pack("", e.totalSupply, e.coinName, e.coinSymbol)
+	if err != nil {
+		return fmt.Errorf("failed to pack constructor arguments: %v", err)
+	}
+	bin := creationCode(erc20.Erc20Bin, constructorArgs)
+	name := "erc20:" + e.coinSymbol
+
+	// Reuse the contract of a previous run while the supplier has the tokens
+	// the run distributes
+	deployed, err := findDeployedContract(e.contracts, e.rpcClient, chainID, e.baseDeployer.From, name, bin)
+	if err != nil {
+		return err
+	}
+	if deployed != nil {
+		instance, err := erc20.NewErc20(*deployed, e.provider)
+		if err != nil {
+			return fmt.Errorf("failed to bind contract: %v", err)
+		}
+		e.contractAddress = deployed
+		e.contract = instance
+
+		balance, err := e.GetSupplierBalance()
+		if err != nil {
+			return fmt.Errorf("failed to get token balance: %v", err)
+		}
+		required := new(big.Int).Mul(e.defaultTransferValue, big.NewInt(int64(e.txCount)))
+		if balance.Cmp(required) >= 0 {
+			log.Info().Msgf("Reusing contract at address: %s", deployed.Hex())
+			log.Info().Msgf("Balance of deployer %s: %s", e.baseDeployer.From.Hex(), balance)
+			return nil
+		}
+		log.Info().Msgf("Deployer has %s %s left of the %s the run needs, redeploying", balance, e.coinSymbol, required)
+		e.contractAddress = nil
+		e.contract = nil
+	}
+
 	// Deploy the contract
 	address, tx, instance, err := erc20.DeployErc20(e.baseDeployer, e.provider, e.totalSupply, e.coinName, e.coinSymbol)
 	if err != nil {
@@ -114,6 +160,10 @@ func (e *ERC20TxBuilder) Initialize() error {
 	e.contract = instance
 	log.Info().Msgf("Contract deployed at address: %s", address.Hex())
 
+	if err := recordDeployedContract(e.contracts, e.rpcClient, chainID, e.baseDeployer.From, name, bin, address); err != nil {
+		return err
+	}
+
 	balance, err := e.GetTokenBalance(e.baseDeployer.From)
 	if err != nil {
 		return fmt.Errorf("failed to get token balance: %v", err)
@@ -148,7 +198,19 @@ func (e *ERC20TxBuilder) EstimateGasForBaseTx() (*big.Int, error) {
 		return nil, fmt.Errorf("failed to construct input: %v", err)
 	}
 
-	gasEstimation, err := e.rpcClient.EstimateGasForContractCall(e.baseDeployer.From, *e.contractAddress, input)
+	msg := ethereum.CallMsg{From: e.baseDeployer.From, To: e.contractAddress, Data: input}
+	if e.feeMode == tooltypes.AccessListFees {
+		e.accessList, err = sharedAccessList(e.rpcClient, e.mnemonic, func(from, to common.Address) (ethereum.CallMsg, error) {
+			input, err := ContructErc20Transfer(to, e.defaultTransferValue)
+			return ethereum.CallMsg{From: from, To: e.contractAddress, Data: input}, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		msg.AccessList = e.accessList
+	}
+
+	gasEstimation, err := e.provider.EstimateGas(context.Background(), msg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to estimate gas: %v", err)
 	}
@@ -206,7 +268,11 @@ func (e *ERC20TxBuilder) GetValue() *big.Int {
 	return e.defaultValue
 }
 
-func (e *ERC20TxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount, numTx int) ([]*types.Transaction, error) {
+func (e *ERC20TxBuilder) GetFees() (*tooltypes.TxFees, error) {
+	return e.rpcClient.SuggestFees(e.feeMode)
+}
+
+func (e *ERC20TxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount, numTx int, fees *tooltypes.TxFees) ([]*types.Transaction, error) {
 	if e.contract == nil {
 		return nil, fmt.Errorf("runtime not initialized")
 	}
@@ -216,13 +282,8 @@ func (e *ERC20TxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccou
 		return nil, fmt.Errorf("failed to get chain ID: %v", err)
 	}
 
-	gasPrice, err := e.rpcClient.GetGasPrice()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %v", err)
-	}
-
 	log.Info().Msgf("Chain ID: %s", chainID.String())
-	log.Info().Msgf("Avg. gas price: %s", gasPrice.String())
+	log.Info().Msgf("Fees (%s): %s", fees.Mode, fees)
 
 	log.Info().Msgf("Constructing %s transfer transactions...", e.coinName)
 	bar := progressbar.Default(int64(numTx))
@@ -243,7 +304,7 @@ func (e *ERC20TxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccou
 			return nil, fmt.Errorf("failed to construct input: %v", err)
 		}
 
-		tx := types.NewTransaction(sender.GetNonce(), *e.contractAddress, new(big.Int), e.gasEstimation.Uint64(), gasPrice, input)
+		tx := utils.NewFeeTransaction(chainID, fees, sender.GetNonce(), *e.contractAddress, new(big.Int), e.gasEstimation.Uint64(), input, e.accessList)
 
 		transactions[i] = tx
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -28,6 +29,8 @@ type ERC721TxBuilder struct {
 	rpcClient       *rpc_client.RpcClient
 	gasEstimation   *big.Int
 	gasPrice        *big.Int
+	feeMode         tooltypes.FeeMode
+	accessList      types.AccessList
 	defaultValue    *big.Int
 	nftName         string
 	nftSymbol       string
@@ -35,9 +38,10 @@ type ERC721TxBuilder struct {
 	contractAddress *common.Address
 	contract        *erc721.Erc721 // You'll need to generate this contract binding
 	baseDeployer    *bind.TransactOpts
+	contracts       tooltypes.ContractRegistry
 }
 
-func NewERC721TxBuilder(mnemonic, url string) (*ERC721TxBuilder, error) {
+func NewERC721TxBuilder(mnemonic, url string, feeMode tooltypes.FeeMode, contracts tooltypes.ContractRegistry) (*ERC721TxBuilder, error) {
 	client, err := ethclient.Dial(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to the Ethereum client: %v", err)
@@ -55,10 +59,12 @@ func NewERC721TxBuilder(mnemonic, url string) (*ERC721TxBuilder, error) {
 		rpcClient:     rpcClient,
 		gasEstimation: big.NewInt(0),
 		gasPrice:      big.NewInt(0),
+		feeMode:       feeMode,
 		defaultValue:  big.NewInt(0),
 		nftName:       "ZEXTokens",
 		nftSymbol:     "ZEXes",
 		nftURL:        "https://really-valuable-nft-page.io",
+		contracts:     contracts,
 	}, nil
 }
 
@@ -73,16 +79,39 @@ func (e *ERC721TxBuilder) Initialize() error {
 		return fmt.Errorf("failed to get chain ID: %v", err)
 	}
 
-	baseGasPrice, err := e.rpcClient.GetGasPrice()
+	fees, err := e.GetFees()
 	if err != nil {
-		return fmt.Errorf("failed to get gas price: %v", err)
+		return err
 	}
 
 	e.baseDeployer, err = bind.NewKeyedTransactorWithChainID(privateKey, chainID)
 	if err != nil {
 		return fmt.Errorf("failed to create transactor: %v", err)
 	}
-	e.baseDeployer.GasPrice = baseGasPrice
+	utils.ApplyFees(e.baseDeployer, fees)
+
+	constructorArgs, err := Erc721Abi.Pack("", e.nftName, e.nftSymbol)
+	if err != nil {
+		return fmt.Errorf("failed to pack constructor arguments: %v", err)
+	}
+	bin := creationCode(erc721.Erc721Bin, constructorArgs)
+	name := "erc721:" + e.nftSymbol
+
+	// Reuse the contract of a previous run
+	deployed, err := findDeployedContract(e.contracts, e.rpcClient, chainID, e.baseDeployer.From, name, bin)
+	if err != nil {
+		return err
+	}
+	if deployed != nil {
+		instance, err := erc721.NewErc721(*deployed, e.provider)
+		if err != nil {
+			return fmt.Errorf("failed to bind contract: %v", err)
+		}
+		e.contractAddress = deployed
+		e.contract = instance
+		log.Info().Msgf("Reusing contract at address: %s", deployed.Hex())
+		return nil
+	}
 
 	// Deploy the contract
 	address, tx, instance, err := erc721.DeployErc721(e.baseDeployer, e.provider, e.nftName, e.nftSymbol)
@@ -99,7 +128,7 @@ func (e *ERC721TxBuilder) Initialize() error {
 	e.contract = instance
 	log.Info().Msgf("Contract deployed at address: %s", address.Hex())
 
-	return nil
+	return recordDeployedContract(e.contracts, e.rpcClient, chainID, e.baseDeployer.From, name, bin, address)
 }
 
 func ContructErc721Mint(tokenURI string) ([]byte, error) {
@@ -121,7 +150,18 @@ func (e *ERC721TxBuilder) EstimateGasForBaseTx() (*big.Int, error) {
 		return nil, fmt.Errorf("failed to construct input: %v", err)
 	}
 
-	gasEstimation, err := e.rpcClient.EstimateGasForContractCall(e.baseDeployer.From, *e.contractAddress, input)
+	msg := ethereum.CallMsg{From: e.baseDeployer.From, To: e.contractAddress, Data: input}
+	if e.feeMode == tooltypes.AccessListFees {
+		e.accessList, err = sharedAccessList(e.rpcClient, e.mnemonic, func(from, _ common.Address) (ethereum.CallMsg, error) {
+			return ethereum.CallMsg{From: from, To: e.contractAddress, Data: input}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		msg.AccessList = e.accessList
+	}
+
+	gasEstimation, err := e.provider.EstimateGas(context.Background(), msg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to estimate gas: %v", err)
 	}
@@ -139,7 +179,11 @@ func (e *ERC721TxBuilder) GetValue() *big.Int {
 	return e.defaultValue
 }
 
-func (e *ERC721TxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount, numTx int) ([]*types.Transaction, error) {
+func (e *ERC721TxBuilder) GetFees() (*tooltypes.TxFees, error) {
+	return e.rpcClient.SuggestFees(e.feeMode)
+}
+
+func (e *ERC721TxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount, numTx int, fees *tooltypes.TxFees) ([]*types.Transaction, error) {
 	if e.contract == nil {
 		return nil, fmt.Errorf("runtime not initialized")
 	}
@@ -149,13 +193,8 @@ func (e *ERC721TxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAcco
 		return nil, fmt.Errorf("failed to get chain ID: %v", err)
 	}
 
-	gasPrice, err := e.rpcClient.GetGasPrice()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %v", err)
-	}
-
 	log.Info().Msgf("Chain ID: %s", chainID.String())
-	log.Info().Msgf("Avg. gas price: %s", gasPrice.String())
+	log.Info().Msgf("Fees (%s): %s", fees.Mode, fees)
 
 	log.Info().Msgf("Constructing %s mint transactions...", e.nftName)
 	bar := progressbar.Default(int64(numTx))
@@ -171,7 +210,7 @@ func (e *ERC721TxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAcco
 			return nil, fmt.Errorf("failed to construct input: %v", err)
 		}
 
-		tx := types.NewTransaction(sender.GetNonce(), *e.contractAddress, new(big.Int), e.gasEstimation.Uint64(), gasPrice, input)
+		tx := utils.NewFeeTransaction(chainID, fees, sender.GetNonce(), *e.contractAddress, new(big.Int), e.gasEstimation.Uint64(), input, e.accessList)
 
 		transactions[i] = tx
 
@@ -0,0 +1,220 @@
+package tx_builder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+	"github.com/rs/zerolog/log"
+	"github.com/schollz/progressbar/v3"
+	"github.com/unifralabs/unifra-benchmark-tool/contract/delegate"
+	"github.com/unifralabs/unifra-benchmark-tool/rpc_client"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// SetCodeTxBuilder sends EIP-7702 set code transactions, in which each
+// account delegates to a counter contract, followed by calls to the
+// delegated accounts that run the counter code
+type SetCodeTxBuilder struct {
+	mnemonic        string
+	url             string
+	provider        *ethclient.Client
+	rpcClient       *rpc_client.RpcClient
+	callGas         *big.Int
+	setCodeGas      *big.Int
+	defaultValue    *big.Int
+	delegateAddress *common.Address
+	baseDeployer    *bind.TransactOpts
+	contracts       tooltypes.ContractRegistry
+}
+
+func NewSetCodeTxBuilder(mnemonic, url string, contracts tooltypes.ContractRegistry) (*SetCodeTxBuilder, error) {
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+	}
+
+	rpcClient, err := rpc_client.NewRpcClientFromEthClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("error creating eth client: %s", err)
+	}
+
+	return &SetCodeTxBuilder{
+		mnemonic:     mnemonic,
+		url:          url,
+		provider:     client,
+		rpcClient:    rpcClient,
+		callGas:      big.NewInt(0),
+		setCodeGas:   big.NewInt(0),
+		defaultValue: big.NewInt(0),
+		contracts:    contracts,
+	}, nil
+}
+
+func (e *SetCodeTxBuilder) Initialize() error {
+	_, privateKey, err := utils.DerivePrivateKeyFromMnemonic(e.mnemonic, 0)
+	if err != nil {
+		return fmt.Errorf("failed to derive private key: %v", err)
+	}
+
+	chainID, err := e.provider.ChainID(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %v", err)
+	}
+
+	fees, err := e.GetFees()
+	if err != nil {
+		return err
+	}
+
+	e.baseDeployer, err = bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to create transactor: %v", err)
+	}
+	utils.ApplyFees(e.baseDeployer, fees)
+
+	// Reuse the delegate of a previous run
+	deployed, err := findDeployedContract(e.contracts, e.rpcClient, chainID, e.baseDeployer.From, "delegate", common.FromHex(delegate.Bin))
+	if err != nil {
+		return err
+	}
+	if deployed != nil {
+		e.delegateAddress = deployed
+		log.Info().Msgf("Reusing delegate contract at address: %s", deployed.Hex())
+		return nil
+	}
+
+	// Deploy the delegate contract
+	address, tx, _, err := bind.DeployContract(e.baseDeployer, abi.ABI{}, common.FromHex(delegate.Bin), e.provider)
+	if err != nil {
+		return fmt.Errorf("failed to deploy delegate contract: %v", err)
+	}
+
+	_, err = bind.WaitDeployed(context.Background(), e.provider, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for delegate contract deployment: %v", err)
+	}
+
+	code, err := e.provider.CodeAt(context.Background(), address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get delegate contract code: %v", err)
+	}
+	if !bytes.Equal(code, common.FromHex(delegate.RuntimeBin)) {
+		return fmt.Errorf("unexpected code deployed at %s", address.Hex())
+	}
+
+	e.delegateAddress = &address
+	log.Info().Msgf("Delegate contract deployed at address: %s", address.Hex())
+
+	return recordDeployedContract(e.contracts, e.rpcClient, chainID, e.baseDeployer.From, "delegate", common.FromHex(delegate.Bin), address)
+}
+
+func (e *SetCodeTxBuilder) EstimateGasForBaseTx() (*big.Int, error) {
+	if e.delegateAddress == nil {
+		return nil, fmt.Errorf("runtime not initialized")
+	}
+
+	// The delegated code runs the same as the delegate contract, plus the
+	// cold access to the delegate
+	gasLimit, err := e.provider.EstimateGas(context.Background(), ethereum.CallMsg{
+		From: e.baseDeployer.From,
+		To:   e.delegateAddress,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+	e.callGas = new(big.Int).SetUint64(gasLimit + params.ColdAccountAccessCostEIP2929)
+
+	// Set code transactions pay for their authorization upfront
+	e.setCodeGas = new(big.Int).Add(e.callGas, new(big.Int).SetUint64(params.CallNewAccountGas))
+
+	log.Info().Msgf("gasEstimation: call %s, set code %s", e.callGas, e.setCodeGas)
+
+	return e.setCodeGas, nil
+}
+
+func (e *SetCodeTxBuilder) GetValue() *big.Int {
+	return e.defaultValue
+}
+
+// GetFees always returns dynamic fees, set code transactions being EIP-1559 transactions
+func (e *SetCodeTxBuilder) GetFees() (*tooltypes.TxFees, error) {
+	return e.rpcClient.SuggestFees(tooltypes.DynamicFees)
+}
+
+// ConstructTransactions first sends a set code transaction from each account,
+// which delegates the account to the delegate contract and runs its code, then
+// calls from each account to the next delegated account
+func (e *SetCodeTxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount, numTx int, fees *tooltypes.TxFees) ([]*types.Transaction, error) {
+	if e.delegateAddress == nil {
+		return nil, fmt.Errorf("runtime not initialized")
+	}
+
+	chainID, err := e.provider.ChainID(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+
+	log.Info().Msgf("Chain ID: %s", chainID.String())
+	log.Info().Msgf("Fees (%s): %s", fees.Mode, fees)
+
+	log.Info().Msg("Constructing set code transactions and delegated calls...")
+	bar := progressbar.Default(int64(numTx))
+
+	transactions := make([]*types.Transaction, numTx)
+
+	for i := 0; i < numTx; i++ {
+		senderIndex := i % len(accounts)
+		receiverIndex := (i + 1) % len(accounts)
+
+		sender := accounts[senderIndex]
+		receiver := accounts[receiverIndex]
+
+		var tx *types.Transaction
+		if i < len(accounts) {
+			// The authorization of the sender itself is checked after the
+			// transaction has incremented its nonce
+			auth, err := types.SignSetCode(sender.PrivateKey, types.SetCodeAuthorization{
+				ChainID: *uint256.MustFromBig(chainID),
+				Address: *e.delegateAddress,
+				Nonce:   sender.GetNonce() + 1,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign authorization: %v", err)
+			}
+
+			tx = types.NewTx(&types.SetCodeTx{
+				ChainID:   uint256.MustFromBig(chainID),
+				Nonce:     sender.GetNonce(),
+				GasTipCap: uint256.MustFromBig(fees.GasTipCap),
+				GasFeeCap: uint256.MustFromBig(fees.GasFeeCap),
+				Gas:       e.setCodeGas.Uint64(),
+				To:        sender.GetAddress(),
+				Value:     uint256.MustFromBig(e.defaultValue),
+				AuthList:  []types.SetCodeAuthorization{auth},
+			})
+			sender.IncrNonce()
+		} else {
+			tx = utils.NewFeeTransaction(chainID, fees, sender.GetNonce(), receiver.GetAddress(), e.defaultValue, e.callGas.Uint64(), nil, nil)
+		}
+
+		transactions[i] = tx
+
+		sender.IncrNonce()
+		bar.Add(1)
+	}
+
+	log.Info().Msgf("Successfully constructed %d transactions", numTx)
+
+	return transactions, nil
+}
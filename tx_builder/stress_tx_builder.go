@@ -0,0 +1,251 @@
+package tx_builder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rs/zerolog/log"
+	"github.com/schollz/progressbar/v3"
+	"github.com/unifralabs/unifra-benchmark-tool/contract/stress"
+	"github.com/unifralabs/unifra-benchmark-tool/rpc_client"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+	"github.com/unifralabs/unifra-benchmark-tool/utils"
+)
+
+// Iterations of the stress calls that calibrate the gas of an iteration
+const (
+	calibrationLow  = 1
+	calibrationHigh = 11
+)
+
+// StressTxBuilder calls a stress contract, which repeats a gas-heavy operation
+// as many times as needed for each transaction to use about gasPerTx gas
+type StressTxBuilder struct {
+	mnemonic        string
+	url             string
+	provider        *ethclient.Client
+	rpcClient       *rpc_client.RpcClient
+	gasEstimation   *big.Int
+	feeMode         tooltypes.FeeMode
+	accessList      types.AccessList
+	kind            stress.Kind
+	gasPerTx        uint64
+	iterations      uint64
+	defaultValue    *big.Int
+	rng             *rand.Rand
+	contractAddress *common.Address
+	baseDeployer    *bind.TransactOpts
+	contracts       tooltypes.ContractRegistry
+}
+
+func NewStressTxBuilder(mnemonic, url string, feeMode tooltypes.FeeMode, kind stress.Kind, gasPerTx uint64, contracts tooltypes.ContractRegistry) (*StressTxBuilder, error) {
+	if _, ok := stress.RuntimeBins[kind]; !ok {
+		return nil, fmt.Errorf("unknown stress workload: %s", kind)
+	}
+	if gasPerTx == 0 {
+		gasPerTx = tooltypes.DefaultGasPerTx
+	}
+
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+	}
+
+	rpcClient, err := rpc_client.NewRpcClientFromEthClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("error creating eth client: %s", err)
+	}
+
+	return &StressTxBuilder{
+		mnemonic:      mnemonic,
+		url:           url,
+		provider:      client,
+		rpcClient:     rpcClient,
+		gasEstimation: big.NewInt(0),
+		feeMode:       feeMode,
+		kind:          kind,
+		gasPerTx:      gasPerTx,
+		defaultValue:  big.NewInt(0),
+		rng:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		contracts:     contracts,
+	}, nil
+}
+
+func (e *StressTxBuilder) Initialize() error {
+	_, privateKey, err := utils.DerivePrivateKeyFromMnemonic(e.mnemonic, 0)
+	if err != nil {
+		return fmt.Errorf("failed to derive private key: %v", err)
+	}
+
+	chainID, err := e.provider.ChainID(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %v", err)
+	}
+
+	header, err := e.provider.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest header: %v", err)
+	}
+	if e.gasPerTx > header.GasLimit {
+		return fmt.Errorf("gas per transaction %d is above the block gas limit %d", e.gasPerTx, header.GasLimit)
+	}
+
+	fees, err := e.GetFees()
+	if err != nil {
+		return err
+	}
+
+	e.baseDeployer, err = bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to create transactor: %v", err)
+	}
+	utils.ApplyFees(e.baseDeployer, fees)
+
+	// Reuse the stress contract of a previous run
+	name := "stress:" + string(e.kind)
+	deployed, err := findDeployedContract(e.contracts, e.rpcClient, chainID, e.baseDeployer.From, name, stress.CreationCode(e.kind))
+	if err != nil {
+		return err
+	}
+	if deployed != nil {
+		e.contractAddress = deployed
+		log.Info().Msgf("Reusing stress contract (%s) at address: %s", e.kind, deployed.Hex())
+		return nil
+	}
+
+	// Deploy the stress contract
+	address, tx, _, err := bind.DeployContract(e.baseDeployer, abi.ABI{}, stress.CreationCode(e.kind), e.provider)
+	if err != nil {
+		return fmt.Errorf("failed to deploy %s stress contract: %v", e.kind, err)
+	}
+
+	_, err = bind.WaitDeployed(context.Background(), e.provider, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for %s stress contract deployment: %v", e.kind, err)
+	}
+
+	e.contractAddress = &address
+	log.Info().Msgf("Stress contract (%s) deployed at address: %s", e.kind, address.Hex())
+
+	return recordDeployedContract(e.contracts, e.rpcClient, chainID, e.baseDeployer.From, name, stress.CreationCode(e.kind), address)
+}
+
+// constructStressCall returns the calldata making the stress contract repeat
+// its operation the given number of times
+func (e *StressTxBuilder) constructStressCall(iterations uint64) []byte {
+	if e.kind == stress.Calldata {
+		input := make([]byte, iterations*32)
+		e.rng.Read(input)
+		return input
+	}
+	return common.LeftPadBytes(new(big.Int).SetUint64(iterations).Bytes(), 32)
+}
+
+func (e *StressTxBuilder) estimateStressCall(iterations uint64) (uint64, error) {
+	return e.provider.EstimateGas(context.Background(), ethereum.CallMsg{
+		From:       e.baseDeployer.From,
+		To:         e.contractAddress,
+		Data:       e.constructStressCall(iterations),
+		AccessList: e.accessList,
+	})
+}
+
+// EstimateGasForBaseTx calibrates the iterations of the calls to reach the gas
+// per transaction, from the gas of two calls of a few iterations
+func (e *StressTxBuilder) EstimateGasForBaseTx() (*big.Int, error) {
+	if e.contractAddress == nil {
+		return nil, fmt.Errorf("runtime not initialized")
+	}
+
+	if e.feeMode == tooltypes.AccessListFees {
+		var err error
+		e.accessList, err = sharedAccessList(e.rpcClient, e.mnemonic, func(from, _ common.Address) (ethereum.CallMsg, error) {
+			return ethereum.CallMsg{From: from, To: e.contractAddress, Data: e.constructStressCall(calibrationLow)}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lowGas, err := e.estimateStressCall(calibrationLow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+	highGas, err := e.estimateStressCall(calibrationHigh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+	if highGas <= lowGas {
+		return nil, fmt.Errorf("failed to calibrate the %s stress calls: gas does not grow with iterations", e.kind)
+	}
+
+	iterationGas := (highGas - lowGas) / (calibrationHigh - calibrationLow)
+	e.iterations = calibrationLow
+	if e.gasPerTx > lowGas {
+		e.iterations += (e.gasPerTx - lowGas) / iterationGas
+	}
+
+	gasEstimation, err := e.estimateStressCall(e.iterations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+	log.Info().Msgf("%s stress gasEstimation: %d (%d iterations of %d gas)", e.kind, gasEstimation, e.iterations, iterationGas)
+
+	// Leave room for the state the other transactions change
+	e.gasEstimation = new(big.Int).SetUint64(gasEstimation + gasEstimation/10)
+	return e.gasEstimation, nil
+}
+
+func (e *StressTxBuilder) GetValue() *big.Int {
+	return e.defaultValue
+}
+
+func (e *StressTxBuilder) GetFees() (*tooltypes.TxFees, error) {
+	return e.rpcClient.SuggestFees(e.feeMode)
+}
+
+func (e *StressTxBuilder) ConstructTransactions(accounts []*tooltypes.SenderAccount, numTx int, fees *tooltypes.TxFees) ([]*types.Transaction, error) {
+	if e.contractAddress == nil || e.iterations == 0 {
+		return nil, fmt.Errorf("runtime not initialized")
+	}
+
+	chainID, err := e.provider.ChainID(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+
+	log.Info().Msgf("Chain ID: %s", chainID.String())
+	log.Info().Msgf("Fees (%s): %s", fees.Mode, fees)
+
+	log.Info().Msgf("Constructing %s stress transactions...", e.kind)
+	bar := progressbar.Default(int64(numTx))
+
+	transactions := make([]*types.Transaction, numTx)
+
+	for i := 0; i < numTx; i++ {
+		senderIndex := i % len(accounts)
+		sender := accounts[senderIndex]
+
+		input := e.constructStressCall(e.iterations)
+
+		tx := utils.NewFeeTransaction(chainID, fees, sender.GetNonce(), *e.contractAddress, e.defaultValue, e.gasEstimation.Uint64(), input, e.accessList)
+
+		transactions[i] = tx
+
+		sender.IncrNonce()
+		bar.Add(1)
+	}
+
+	log.Info().Msgf("Successfully constructed %d transactions", numTx)
+
+	return transactions, nil
+}
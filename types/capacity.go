@@ -0,0 +1,43 @@
+package types
+
+// SLO is the service level a node must meet for a rate to count as sustained
+type SLO struct {
+	MinSuccess float64 `json:"min_success"`
+	MaxP99     float64 `json:"max_p99"` // seconds
+}
+
+// CapacitySearchParameters bound the search of the max sustainable rate
+type CapacitySearchParameters struct {
+	StartRate     int `json:"start_rate"`
+	MaxRate       int `json:"max_rate"`
+	Precision     int `json:"precision"`
+	ProbeDuration int `json:"probe_duration"`
+	SLO           SLO `json:"slo"`
+}
+
+// CapacityProbe records a single attack of a capacity search. Its metrics are
+// those of the deep output, counting JSON-RPC errors as failures.
+type CapacityProbe struct {
+	Rate       int      `json:"rate"`
+	Success    *float64 `json:"success"`
+	P99        *float64 `json:"p99"`
+	Throughput *float64 `json:"throughput"`
+	Passed     bool     `json:"passed"`
+}
+
+// CapacityResult is the max sustainable rate found for a node, 0 if even the
+// start rate violates the SLO
+type CapacityResult struct {
+	Node     string          `json:"node"`
+	Capacity int             `json:"capacity"`
+	Probes   []CapacityProbe `json:"probes"`
+}
+
+type CapacityResultsPayload struct {
+	CLIArgs    []string                  `json:"cli_args"`
+	TRunStart  int64                     `json:"t_run_start"`
+	TRunEnd    int64                     `json:"t_run_end"`
+	Nodes      Nodes                     `json:"nodes"`
+	Parameters CapacitySearchParameters  `json:"parameters"`
+	Results    map[string]CapacityResult `json:"results"`
+}
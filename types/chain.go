@@ -0,0 +1,14 @@
+package types
+
+// ChainInfo describes the blocks and the state a node can serve
+type ChainInfo struct {
+	Head          uint64 `json:"head"`
+	EarliestState uint64 `json:"earliest_state"`
+	Archive       bool   `json:"archive"`
+}
+
+// BlockRange is an inclusive range of block numbers
+type BlockRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
@@ -0,0 +1,24 @@
+package types
+
+// ContractCallSpec describes the contract of a CONTRACT transaction benchmark
+// and the call made by every transaction. Arguments are templates, parsed
+// according to the ABI types, in which these placeholders are replaced per
+// transaction:
+//
+//	{sender}          address of the sending account
+//	{receiver}        address of the next account
+//	{account}         index of the sending account
+//	{index}           index of the transaction
+//	{random}          random value of the argument type
+//	{random:MIN..MAX} random integer between MIN and MAX included
+//
+// Array arguments are written as [a,b,c], each element being a template.
+type ContractCallSpec struct {
+	ABI             string   `json:"abi"`                        // path of the ABI JSON file
+	Bin             string   `json:"bin,omitempty"`              // path of the hex creation code, to deploy the contract
+	Address         string   `json:"address,omitempty"`          // already deployed contract, instead of Bin
+	ConstructorArgs []string `json:"constructor_args,omitempty"` // sent by the admin account, so only {sender} refers to it
+	Method          string   `json:"method"`
+	Args            []string `json:"args,omitempty"`
+	Value           string   `json:"value,omitempty"` // wei sent with each call
+}
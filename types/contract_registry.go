@@ -0,0 +1,19 @@
+package types
+
+// DeployedContract records a benchmark contract deployed by an account, so
+// that later runs can reuse it
+type DeployedContract struct {
+	ChainID  string
+	Deployer string
+	Name     string
+	Address  string
+	BinHash  string // hash of the creation code, constructor arguments included
+	CodeHash string // hash of the deployed code
+}
+
+// ContractRegistry stores the deployed contracts per chain ID and deployer
+type ContractRegistry interface {
+	// GetContract returns nil if the deployer has no contract of that name
+	GetContract(chainID, deployer, name string) (*DeployedContract, error)
+	SaveContract(contract DeployedContract) error
+}
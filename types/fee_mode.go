@@ -0,0 +1,60 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FeeMode is the transaction type, and pricing, of the runtime transactions
+type FeeMode string
+
+const (
+	// LegacyFees builds legacy transactions priced with eth_gasPrice
+	LegacyFees FeeMode = "legacy"
+	// AccessListFees builds EIP-2930 access list transactions priced with eth_gasPrice,
+	// listing what eth_createAccessList finds the calls of a tx type share
+	AccessListFees FeeMode = "access-list"
+	// DynamicFees builds EIP-1559 transactions with a tip and a fee cap
+	// derived from eth_feeHistory
+	DynamicFees FeeMode = "dynamic-fee"
+)
+
+// ParseFeeMode parses a fee mode case-insensitively, legacy by default
+func ParseFeeMode(s string) (FeeMode, error) {
+	switch feeMode := FeeMode(strings.ToLower(s)); feeMode {
+	case "":
+		return LegacyFees, nil
+	case LegacyFees, AccessListFees, DynamicFees:
+		return feeMode, nil
+	default:
+		return "", fmt.Errorf("unknown fee mode: %s", s)
+	}
+}
+
+// TxFees are the fees of a transaction: a gas price, or a tip and a fee cap
+// in the dynamic fee mode
+type TxFees struct {
+	Mode      FeeMode
+	GasPrice  *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+
+	// BlobFeeCap is the max price per blob gas of blob transactions, nil otherwise
+	BlobFeeCap *big.Int
+}
+
+// MaxGasPrice is the highest price per gas a transaction can be charged
+func (f *TxFees) MaxGasPrice() *big.Int {
+	if f.Mode == DynamicFees {
+		return f.GasFeeCap
+	}
+	return f.GasPrice
+}
+
+func (f *TxFees) String() string {
+	if f.Mode == DynamicFees {
+		return fmt.Sprintf("tip %s, fee cap %s", f.GasTipCap, f.GasFeeCap)
+	}
+	return fmt.Sprintf("gas price %s", f.GasPrice)
+}
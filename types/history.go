@@ -0,0 +1,75 @@
+package types
+
+import "encoding/json"
+
+// RunKind is the benchmark that a run of the history ran
+type RunKind string
+
+const (
+	RpcRun      RunKind = "rpc"
+	CapacityRun RunKind = "capacity"
+	TxRun       RunKind = "tx"
+)
+
+// AttackMetrics are the metrics of one attack of a node, or of one probe of a
+// capacity search
+type AttackMetrics struct {
+	Node           string   `json:"node"`
+	Attack         int      `json:"attack"`
+	TargetRate     int      `json:"target_rate"`
+	TargetDuration int      `json:"target_duration"`
+	Requests       int      `json:"requests"`
+	ActualRate     *float64 `json:"actual_rate"`
+	Throughput     *float64 `json:"throughput"`
+	Success        *float64 `json:"success"`
+	Min            *float64 `json:"min"`
+	Mean           *float64 `json:"mean"`
+	P50            *float64 `json:"p50"`
+	P90            *float64 `json:"p90"`
+	P95            *float64 `json:"p95"`
+	P99            *float64 `json:"p99"`
+	Max            *float64 `json:"max"`
+}
+
+// TxBlock is a block of a transaction benchmark
+type TxBlock struct {
+	BlockNum       uint64  `json:"block_num"`
+	CreatedAt      uint64  `json:"created_at"`
+	NumTxs         int     `json:"num_txs"`
+	GasUsed        uint64  `json:"gas_used"`
+	GasLimit       uint64  `json:"gas_limit"`
+	GasUtilization float64 `json:"gas_utilization"`
+	BlockTime      uint64  `json:"block_time"`
+	NumBlobs       int     `json:"num_blobs"`
+	BlobGasUsed    uint64  `json:"blob_gas_used"`
+	ExcessBlobGas  uint64  `json:"excess_blob_gas"`
+}
+
+// TxRunResults are the results of a transaction benchmark
+type TxRunResults struct {
+	TxType   TxType    `json:"tx_type"`
+	TPS      float64   `json:"tps"`
+	MinedTxs int       `json:"mined_txs"`
+	Blocks   []TxBlock `json:"blocks"`
+}
+
+// RunRecord is a run of the history. Parameters are those of the run kind:
+// the test generation parameters of RPC runs, the search parameters of
+// capacity runs and the tx scenario of tx runs.
+type RunRecord struct {
+	ID         int64           `json:"id"`
+	Kind       RunKind         `json:"kind"`
+	TestName   string          `json:"test_name"`
+	TRunStart  int64           `json:"t_run_start"`
+	TRunEnd    int64           `json:"t_run_end"`
+	CLIArgs    []string        `json:"cli_args"`
+	Parameters json.RawMessage `json:"parameters"`
+	Nodes      Nodes           `json:"nodes"`
+	Attacks    []AttackMetrics `json:"attacks,omitempty"`
+	Tx         *TxRunResults   `json:"tx,omitempty"`
+}
+
+// RunHistory records the runs of the benchmarks
+type RunHistory interface {
+	SaveRun(run RunRecord) (int64, error)
+}
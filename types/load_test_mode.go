@@ -0,0 +1,105 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// LoadTestModeParameters describes how a load test mode schedules its attacks.
+// Rates are in requests per second and durations in seconds.
+type LoadTestModeParameters struct {
+	Mode LoadTestMode `json:"mode"`
+
+	// Stress: rates from BaseRate up to PeakRate by Step, StepDuration each
+	// Spike: BaseRate for StepDuration, PeakRate for BurstDuration, then BaseRate again
+	// Soak: BaseRate for Duration, with a checkpoint every CheckpointInterval
+	BaseRate           int `json:"base_rate"`
+	PeakRate           int `json:"peak_rate,omitempty"`
+	Step               int `json:"step,omitempty"`
+	StepDuration       int `json:"step_duration,omitempty"`
+	BurstDuration      int `json:"burst_duration,omitempty"`
+	Duration           int `json:"duration,omitempty"`
+	CheckpointInterval int `json:"checkpoint_interval,omitempty"`
+}
+
+// LoadTestGenerators maps each load test mode to the generator of its schedule
+var LoadTestGenerators = map[LoadTestMode]LoadTestGenerator{
+	StressMode: GenerateStressSchedule,
+	SpikeMode:  GenerateSpikeSchedule,
+	SoakMode:   GenerateSoakSchedule,
+}
+
+// GenerateLoadTestSchedule returns the rates and durations of the attacks of a load test mode
+func GenerateLoadTestSchedule(params LoadTestModeParameters) ([]int, []int, error) {
+	generator, ok := LoadTestGenerators[params.Mode]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown load test mode: %q", params.Mode)
+	}
+	return generator(params)
+}
+
+// GenerateStressSchedule steps the rate up from the base rate to the peak rate
+func GenerateStressSchedule(params LoadTestModeParameters) ([]int, []int, error) {
+	if params.BaseRate <= 0 || params.PeakRate < params.BaseRate {
+		return nil, nil, errors.New("stress mode requires 0 < base rate <= peak rate")
+	}
+	if params.Step <= 0 {
+		return nil, nil, errors.New("stress mode requires a positive step")
+	}
+	if params.StepDuration <= 0 {
+		return nil, nil, errors.New("stress mode requires a positive step duration")
+	}
+
+	rates := []int{}
+	durations := []int{}
+	for rate := params.BaseRate; rate <= params.PeakRate; rate += params.Step {
+		rates = append(rates, rate)
+		durations = append(durations, params.StepDuration)
+	}
+	// Always finish at the peak rate
+	if rates[len(rates)-1] != params.PeakRate {
+		rates = append(rates, params.PeakRate)
+		durations = append(durations, params.StepDuration)
+	}
+
+	return rates, durations, nil
+}
+
+// GenerateSpikeSchedule surrounds a burst at the peak rate with the base rate
+func GenerateSpikeSchedule(params LoadTestModeParameters) ([]int, []int, error) {
+	if params.BaseRate <= 0 || params.PeakRate <= params.BaseRate {
+		return nil, nil, errors.New("spike mode requires 0 < base rate < peak rate")
+	}
+	if params.StepDuration <= 0 || params.BurstDuration <= 0 {
+		return nil, nil, errors.New("spike mode requires positive baseline and burst durations")
+	}
+
+	rates := []int{params.BaseRate, params.PeakRate, params.BaseRate}
+	durations := []int{params.StepDuration, params.BurstDuration, params.StepDuration}
+	return rates, durations, nil
+}
+
+// GenerateSoakSchedule holds the base rate for the whole duration, split into
+// one attack per checkpoint so that the results show the drift over time
+func GenerateSoakSchedule(params LoadTestModeParameters) ([]int, []int, error) {
+	if params.BaseRate <= 0 {
+		return nil, nil, errors.New("soak mode requires a positive rate")
+	}
+	if params.Duration <= 0 {
+		return nil, nil, errors.New("soak mode requires a positive duration")
+	}
+
+	interval := params.CheckpointInterval
+	if interval <= 0 || interval > params.Duration {
+		interval = params.Duration
+	}
+
+	rates := []int{}
+	durations := []int{}
+	for remaining := params.Duration; remaining > 0; remaining -= interval {
+		rates = append(rates, params.BaseRate)
+		durations = append(durations, min(interval, remaining))
+	}
+
+	return rates, durations, nil
+}
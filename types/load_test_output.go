@@ -1,5 +1,7 @@
 package types
 
+import "sort"
+
 // Load tests outputs
 
 type RawLoadTestOutputDatum struct {
@@ -47,6 +49,8 @@ type LoadTestOutputDatum struct {
 	DeepRawOutput         *string                                      `json:"deep_raw_output"`
 	DeepMetrics           map[ResponseCategory]LoadTestDeepOutputDatum `json:"deep_metrics"`
 	DeepRPCErrorPairs     []ErrorPair                                  `json:"deep_rpc_error_pairs"`
+	DeepRPCErrorGroups    []RpcErrorGroup                              `json:"deep_rpc_error_groups"`
+	DeepMethodMetrics     map[string]LoadTestDeepOutputDatum           `json:"deep_method_metrics"`
 }
 
 type ResponseCategory string
@@ -57,8 +61,17 @@ const (
 	FailedResponses     ResponseCategory = "failed"
 )
 
+// ErrorPair holds the originating request and the JSON-RPC error returned for it
 type ErrorPair [2]interface{}
 
+// RpcErrorGroup counts the JSON-RPC errors of an attack sharing a method and error code
+type RpcErrorGroup struct {
+	Method  string `json:"method"`
+	Code    *int   `json:"code"`
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
 type LoadTestDeepOutputDatum struct {
 	TargetRate            int            `json:"target_rate"`
 	ActualRate            *float64       `json:"actual_rate"`
@@ -108,6 +121,8 @@ type LoadTestOutput struct {
 	DeepRawOutput         []*string                               `json:"deep_raw_output"`
 	DeepMetrics           map[ResponseCategory]LoadTestDeepOutput `json:"deep_metrics"`
 	DeepRPCErrorPairs     [][]ErrorPair                           `json:"deep_rpc_error_pairs"`
+	DeepRPCErrorGroups    [][]RpcErrorGroup                       `json:"deep_rpc_error_groups"`
+	DeepMethodMetrics     map[string]LoadTestDeepOutput           `json:"deep_method_metrics"`
 }
 
 type LoadTestDeepOutput struct {
@@ -229,5 +244,159 @@ func BuildLoadTestOutput(listOfMaps []*LoadTestOutputDatum) LoadTestOutput {
 		result.DeepRawOutput[i] = m.DeepRawOutput
 	}
 
+	if listOfMaps[0].DeepMetrics != nil {
+		result.DeepMetrics = make(map[ResponseCategory]LoadTestDeepOutput)
+		for _, category := range []ResponseCategory{AllResponses, SuccessfulResponses, FailedResponses} {
+			categoryMetrics := make([]LoadTestDeepOutputDatum, len(listOfMaps))
+			for i, m := range listOfMaps {
+				categoryMetrics[i] = m.DeepMetrics[category]
+			}
+			result.DeepMetrics[category] = BuildLoadTestDeepOutput(categoryMetrics)
+		}
+
+		result.DeepRPCErrorPairs = make([][]ErrorPair, len(listOfMaps))
+		for i, m := range listOfMaps {
+			result.DeepRPCErrorPairs[i] = m.DeepRPCErrorPairs
+		}
+
+		result.DeepRPCErrorGroups = make([][]RpcErrorGroup, len(listOfMaps))
+		for i, m := range listOfMaps {
+			result.DeepRPCErrorGroups[i] = m.DeepRPCErrorGroups
+		}
+
+		result.DeepMethodMetrics = make(map[string]LoadTestDeepOutput)
+		for _, m := range listOfMaps {
+			for method := range m.DeepMethodMetrics {
+				if _, ok := result.DeepMethodMetrics[method]; ok {
+					continue
+				}
+				methodMetrics := make([]LoadTestDeepOutputDatum, len(listOfMaps))
+				for i, mm := range listOfMaps {
+					methodMetrics[i] = mm.DeepMethodMetrics[method]
+				}
+				result.DeepMethodMetrics[method] = BuildLoadTestDeepOutput(methodMetrics)
+			}
+		}
+	}
+
 	return result
 }
+
+func BuildLoadTestDeepOutput(listOfMaps []LoadTestDeepOutputDatum) LoadTestDeepOutput {
+	n := len(listOfMaps)
+	result := LoadTestDeepOutput{
+		TargetRate:            make([]int, n),
+		ActualRate:            make([]*float64, n),
+		TargetDuration:        make([]int, n),
+		ActualDuration:        make([]*float64, n),
+		Requests:              make([]int, n),
+		Throughput:            make([]*float64, n),
+		Success:               make([]*float64, n),
+		Min:                   make([]*float64, n),
+		Mean:                  make([]*float64, n),
+		P50:                   make([]*float64, n),
+		P90:                   make([]*float64, n),
+		P95:                   make([]*float64, n),
+		P99:                   make([]*float64, n),
+		Max:                   make([]*float64, n),
+		StatusCodes:           make([]map[string]int, n),
+		Errors:                make([][]string, n),
+		FirstRequestTimestamp: make([]*string, n),
+		LastRequestTimestamp:  make([]*string, n),
+		LastResponseTimestamp: make([]*string, n),
+		FinalWaitTime:         make([]*float64, n),
+		NInvalidJSONErrors:    make([]int, n),
+		NRPCErrors:            make([]int, n),
+	}
+
+	for i, m := range listOfMaps {
+		result.TargetRate[i] = m.TargetRate
+		result.ActualRate[i] = m.ActualRate
+		result.TargetDuration[i] = m.TargetDuration
+		result.ActualDuration[i] = m.ActualDuration
+		result.Requests[i] = m.Requests
+		result.Throughput[i] = m.Throughput
+		result.Success[i] = m.Success
+		result.Min[i] = m.Min
+		result.Mean[i] = m.Mean
+		result.P50[i] = m.P50
+		result.P90[i] = m.P90
+		result.P95[i] = m.P95
+		result.P99[i] = m.P99
+		result.Max[i] = m.Max
+		result.StatusCodes[i] = m.StatusCodes
+		result.Errors[i] = m.Errors
+		result.FirstRequestTimestamp[i] = m.FirstRequestTimestamp
+		result.LastRequestTimestamp[i] = m.LastRequestTimestamp
+		result.LastResponseTimestamp[i] = m.LastResponseTimestamp
+		result.FinalWaitTime[i] = m.FinalWaitTime
+		result.NInvalidJSONErrors[i] = m.NInvalidJSONErrors
+		result.NRPCErrors[i] = m.NRPCErrors
+	}
+
+	return result
+}
+
+// MedianLoadTestOutput combines the outputs of repeats of the same load test.
+// Rates, latencies and success are the median of the repeats, requests are
+// summed. Per-request details such as errors and deep metrics are left to
+// the individual repeats.
+func MedianLoadTestOutput(outputs []LoadTestOutput) LoadTestOutput {
+	if len(outputs) == 0 {
+		return LoadTestOutput{}
+	}
+	if len(outputs) == 1 {
+		return outputs[0]
+	}
+
+	first := outputs[0]
+	result := LoadTestOutput{
+		TargetRate:     first.TargetRate,
+		TargetDuration: first.TargetDuration,
+		Requests:       make([]int, len(first.TargetRate)),
+	}
+	for _, output := range outputs {
+		for i := range result.Requests {
+			if i < len(output.Requests) {
+				result.Requests[i] += output.Requests[i]
+			}
+		}
+	}
+
+	columns := func(o *LoadTestOutput) []*[]*float64 {
+		return []*[]*float64{
+			&o.ActualRate, &o.ActualDuration, &o.Throughput, &o.Success,
+			&o.Min, &o.Mean, &o.P50, &o.P90, &o.P95, &o.P99, &o.Max, &o.FinalWaitTime,
+		}
+	}
+	resultColumns := columns(&result)
+	for c := range resultColumns {
+		column := make([]*float64, len(first.TargetRate))
+		for i := range column {
+			values := []float64{}
+			for o := range outputs {
+				outputColumn := *columns(&outputs[o])[c]
+				if i < len(outputColumn) && outputColumn[i] != nil {
+					values = append(values, *outputColumn[i])
+				}
+			}
+			column[i] = median(values)
+		}
+		*resultColumns[c] = column
+	}
+
+	return result
+}
+
+func median(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	m := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		m = (sorted[len(sorted)/2-1] + m) / 2
+	}
+	return &m
+}
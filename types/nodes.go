@@ -9,3 +9,15 @@ type Node struct {
 }
 
 type Nodes map[string]Node
+
+// NodesMode is how a load test is run against several nodes
+type NodesMode string
+
+const (
+	// SequentialNodes runs the load test against one node at a time, in a
+	// shuffled order on each repeat to cancel out time-of-day effects
+	SequentialNodes NodesMode = "sequential"
+	// SimultaneousNodes runs each attack against all nodes in parallel, with
+	// the same calls
+	SimultaneousNodes NodesMode = "simultaneous"
+)
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Tolerance is how far a metric may drift from its baseline, in percent,
+// before the drift counts as a regression. Drop tolerances bound the decrease
+// of higher-is-better metrics (throughput=-5%), the others bound the increase
+// of lower-is-better metrics (p99=+10%).
+type Tolerance struct {
+	Metric  string
+	Percent float64
+	Drop    bool
+}
+
+// Allows tells whether a drift of the metric, in percent, is within the tolerance
+func (t Tolerance) Allows(delta float64) bool {
+	if t.Drop {
+		return delta >= -t.Percent
+	}
+	return delta <= t.Percent
+}
+
+// ParseTolerances parses comma-separated tolerances such as "p99=+10%,throughput=-5%".
+// The sign is required, it tells the direction in which the metric regresses.
+func ParseTolerances(s string) ([]Tolerance, error) {
+	tolerances := []Tolerance{}
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		metric, value, ok := strings.Cut(field, "=")
+		metric = strings.TrimSpace(metric)
+		value = strings.TrimSuffix(strings.TrimSpace(value), "%")
+		if !ok || metric == "" || value == "" {
+			return nil, fmt.Errorf("invalid tolerance %q, expected METRIC=+PERCENT or METRIC=-PERCENT", field)
+		}
+		if value[0] != '+' && value[0] != '-' {
+			return nil, fmt.Errorf("tolerance %q must be signed: + bounds an increase, - a decrease", field)
+		}
+
+		percent, err := strconv.ParseFloat(value[1:], 64)
+		if err != nil || percent < 0 || math.IsInf(percent, 0) || math.IsNaN(percent) {
+			return nil, fmt.Errorf("invalid tolerance percent in %q", field)
+		}
+		if seen[metric] {
+			return nil, fmt.Errorf("tolerance of %s given twice", metric)
+		}
+		seen[metric] = true
+
+		tolerances = append(tolerances, Tolerance{Metric: metric, Percent: percent, Drop: value[0] == '-'})
+	}
+
+	if len(tolerances) == 0 {
+		return nil, fmt.Errorf("no tolerance given")
+	}
+	return tolerances, nil
+}
+
+// RegressionCheck is the comparison of a metric of a run to its baseline.
+// Current is NaN when the run lacks a result of the baseline.
+type RegressionCheck struct {
+	Subject   string
+	Metric    string
+	Baseline  float64
+	Current   float64
+	Delta     float64
+	Tolerance Tolerance
+	Regressed bool
+}
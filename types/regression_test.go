@@ -0,0 +1,78 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTolerances(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Tolerance
+		wantErr string
+	}{
+		{
+			name:  "signed percents",
+			input: "p99=+10%, throughput=-5",
+			want: []Tolerance{
+				{Metric: "p99", Percent: 10},
+				{Metric: "throughput", Percent: 5, Drop: true},
+			},
+		},
+		{name: "unsigned value", input: "p99=10%", wantErr: "must be signed"},
+		{name: "duplicate metric", input: "p99=+10%,p99=+5%", wantErr: "given twice"},
+		{name: "negative percent", input: "p99=+-10%", wantErr: "invalid tolerance percent"},
+		{name: "missing value", input: "p99=", wantErr: "invalid tolerance"},
+		{name: "missing metric", input: "=+10%", wantErr: "invalid tolerance"},
+		{name: "empty", input: " , ", wantErr: "no tolerance given"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTolerances(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseTolerances(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTolerances(%q) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTolerances(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToleranceAllows(t *testing.T) {
+	increase := Tolerance{Metric: "p99", Percent: 10}
+	drop := Tolerance{Metric: "throughput", Percent: 5, Drop: true}
+
+	tests := []struct {
+		name      string
+		tolerance Tolerance
+		delta     float64
+		want      bool
+	}{
+		{"increase within", increase, 9.9, true},
+		{"increase at the bound", increase, 10, true},
+		{"increase beyond", increase, 10.1, false},
+		{"increase any decrease", increase, -50, true},
+		{"drop within", drop, -4.9, true},
+		{"drop at the bound", drop, -5, true},
+		{"drop beyond", drop, -5.1, false},
+		{"drop any increase", drop, 50, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tolerance.Allows(tt.delta); got != tt.want {
+				t.Errorf("%+v.Allows(%g) = %t, want %t", tt.tolerance, tt.delta, got, tt.want)
+			}
+		})
+	}
+}
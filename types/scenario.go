@@ -0,0 +1,49 @@
+package types
+
+// Scenario is a declarative benchmark definition, loaded from a YAML or JSON file
+type Scenario struct {
+	Name      string       `json:"name"`
+	Nodes     []string     `json:"nodes"` // NAME=URL
+	OutputDir string       `json:"output_dir,omitempty"`
+	Rpc       *RpcScenario `json:"rpc,omitempty"`
+	Tx        *TxScenario  `json:"tx,omitempty"`
+}
+
+// RpcScenario describes an RPC load test. Rates and durations can be given
+// explicitly or generated by a load test mode.
+type RpcScenario struct {
+	Network        string                    `json:"network,omitempty"`
+	RandomSeed     *RandomSeed               `json:"random_seed,omitempty"`
+	Workload       Workload                  `json:"workload,omitempty"`
+	Rates          []int                     `json:"rates,omitempty"`
+	Durations      []int                     `json:"durations,omitempty"`
+	Mode           *LoadTestModeParameters   `json:"mode,omitempty"`
+	BlockRange     string                    `json:"block_range,omitempty"`
+	VegetaArgs     *string                   `json:"vegeta_args,omitempty"`
+	DeepOutput     []DeepOutput              `json:"deep_output,omitempty"`
+	SamplesDir     string                    `json:"samples_dir,omitempty"`
+	HarvestBlocks  int                       `json:"harvest_blocks,omitempty"`
+	CapacitySearch *CapacitySearchParameters `json:"capacity_search,omitempty"`
+	NodesMode      NodesMode                 `json:"nodes_mode,omitempty"`
+	Repeats        int                       `json:"repeats,omitempty"`
+	Verify         *VerificationParameters   `json:"verify,omitempty"`
+
+	// SaveTest saves the generated load test to a file, and Replay runs a
+	// saved load test instead of generating one
+	SaveTest string `json:"save_test,omitempty"`
+	Replay   string `json:"replay,omitempty"`
+}
+
+// TxScenario describes a transaction benchmark. The mnemonic of the funded
+// account is never part of a scenario and comes from the environment.
+type TxScenario struct {
+	Type         string `json:"type"`
+	Accounts     int    `json:"accounts"`
+	Transactions int    `json:"transactions"`
+	BatchSize    int    `json:"batch_size"`
+	FeeMode      string `json:"fee_mode,omitempty"`
+	BlobsPerTx   int    `json:"blobs_per_tx,omitempty"` // blob transactions only, 1 by default
+	GasPerTx     uint64 `json:"gas_per_tx,omitempty"`   // stress workloads only, DefaultGasPerTx by default
+
+	Contract *ContractCallSpec `json:"contract,omitempty"` // contract transactions only
+}
@@ -16,12 +16,15 @@ type MultiVegetaArgs []VegetaArgs
 type VegetaArgsShorthand interface{} // Can be VegetaArgs or MultiVegetaArgs
 
 type TestGenerationParameters struct {
-	TestName   string              `json:"test_name"`
-	RandomSeed RandomSeed          `json:"random_seed"`
-	Rates      []int               `json:"rates"`
-	Durations  []int               `json:"durations"`
-	VegetaArgs VegetaArgsShorthand `json:"vegeta_args"`
-	Network    string              `json:"network"`
+	TestName   string                  `json:"test_name"`
+	RandomSeed RandomSeed              `json:"random_seed"`
+	Rates      []int                   `json:"rates"`
+	Durations  []int                   `json:"durations"`
+	VegetaArgs VegetaArgsShorthand     `json:"vegeta_args"`
+	Network    string                  `json:"network"`
+	Workload   Workload                `json:"workload,omitempty"`
+	BlockRange *BlockRange             `json:"block_range,omitempty"`
+	Mode       *LoadTestModeParameters `json:"mode,omitempty"`
 }
 
 type LoadTest struct {
@@ -29,6 +32,22 @@ type LoadTest struct {
 	Attacks        []VegetaAttack           `json:"attacks"`
 }
 
+// SavedLoadTest is the file format of a generated LoadTest. Attacks that share
+// the same calls, as repeated calls do, store them only once.
+type SavedLoadTest struct {
+	Version        int                      `json:"version"`
+	TestParameters TestGenerationParameters `json:"test_parameters"`
+	Calls          [][]*JsonrpcMessage      `json:"calls"`
+	Attacks        []SavedVegetaAttack      `json:"attacks"`
+}
+
+type SavedVegetaAttack struct {
+	Rate       int     `json:"rate"`
+	Duration   int     `json:"duration"`
+	Calls      int     `json:"calls"` // index into SavedLoadTest.Calls
+	VegetaArgs *string `json:"vegeta_args"`
+}
+
 type LoadTestColumnWise struct {
 	Rates      []int           `json:"rates"`
 	Durations  []int           `json:"durations"`
@@ -44,7 +63,9 @@ const (
 	SoakMode   LoadTestMode = "soak"
 )
 
-type LoadTestGenerator func(...interface{}) []VegetaAttack
+// LoadTestGenerator turns the parameters of a load test mode into the rates
+// and durations of its attacks
+type LoadTestGenerator func(params LoadTestModeParameters) (rates []int, durations []int, err error)
 type MultiLoadTestGenerator func(...interface{}) map[string]LoadTest
 
 type RunType string
@@ -74,4 +95,8 @@ type SingleRunResultsPayload struct {
 	TRunEnd            int64                     `json:"t_run_end"`
 	Nodes              Nodes                     `json:"nodes"`
 	Results            map[string]LoadTestOutput `json:"results"`
+
+	// Repeats holds the output of every repeat of each node, when the load
+	// test was repeated and Results holds their medians
+	Repeats map[string][]LoadTestOutput `json:"repeats,omitempty"`
 }
@@ -14,8 +14,12 @@ type TxBuilder interface {
 	// Returns the value of each cycle transaction, if any
 	GetValue() *big.Int
 
-	// Constructs the specific runtime transactions
-	ConstructTransactions(accounts []*SenderAccount, numTxs int) ([]*types.Transaction, error)
+	// Returns the current fees of the runtime transactions
+	GetFees() (*TxFees, error)
+
+	// Constructs the specific runtime transactions with the fees the
+	// sub-accounts were funded for
+	ConstructTransactions(accounts []*SenderAccount, numTxs int, fees *TxFees) ([]*types.Transaction, error)
 
 	// Initializes the runtime
 	Initialize() error
@@ -42,3 +46,9 @@ type Erc20TxBuilder interface {
 type Erc721TxBuilder interface {
 	TxBuilder
 }
+
+type BlobTxBuilder interface {
+	TxBuilder
+	// Returns the blob gas of each runtime transaction
+	GetBlobGas() uint64
+}
@@ -1,9 +1,64 @@
 package types
 
+import (
+	"fmt"
+	"strings"
+)
+
 type TxType string
 
 const (
-	EOA    TxType = "EOA"
-	ERC20  TxType = "ERC20"
-	ERC721 TxType = "ERC721"
+	EOA      TxType = "EOA"
+	ERC20    TxType = "ERC20"
+	ERC721   TxType = "ERC721"
+	BLOB     TxType = "BLOB"
+	SETCODE  TxType = "SETCODE"
+	CONTRACT TxType = "CONTRACT"
+
+	// Execution-layer stress workloads
+	SSTORE   TxType = "SSTORE"
+	SLOAD    TxType = "SLOAD"
+	KECCAK   TxType = "KECCAK"
+	CALLDATA TxType = "CALLDATA"
+	LOGS     TxType = "LOGS"
+	CREATE   TxType = "CREATE"
 )
+
+// MaxBlobsPerTx is the most blobs a transaction can carry. It is the Cancun
+// cap of 6 blobs per block, which every blob-carrying chain accepts: Prague
+// raises the block cap to 9, but Osaka caps transactions at 6 again.
+const MaxBlobsPerTx = 6
+
+// DefaultGasPerTx is the gas that each transaction of a stress workload uses by default
+const DefaultGasPerTx = 1_000_000
+
+// TxOptions tune the runtime transactions of a tx benchmark
+type TxOptions struct {
+	FeeMode    FeeMode
+	BlobsPerTx int               // blobs carried by each BLOB transaction
+	Contract   *ContractCallSpec // contract called by CONTRACT transactions
+	GasPerTx   uint64            // gas used by each stress transaction
+	Contracts  ContractRegistry  // benchmark contracts deployed by earlier runs, nil to always deploy
+	History    RunHistory        // records the run, nil to not record it
+}
+
+// IsStress tells whether a transaction type is a stress workload
+func (t TxType) IsStress() bool {
+	switch t {
+	case SSTORE, SLOAD, KECCAK, CALLDATA, LOGS, CREATE:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseTxType parses a transaction type case-insensitively, e.g. "erc20"
+func ParseTxType(s string) (TxType, error) {
+	switch txType := TxType(strings.ToUpper(s)); txType {
+	case EOA, ERC20, ERC721, BLOB, SETCODE, CONTRACT,
+		SSTORE, SLOAD, KECCAK, CALLDATA, LOGS, CREATE:
+		return txType, nil
+	default:
+		return "", fmt.Errorf("unknown transaction type: %s", s)
+	}
+}
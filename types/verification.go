@@ -0,0 +1,47 @@
+package types
+
+import "encoding/json"
+
+// VerificationParameters enable the comparison of the responses of the nodes
+// to identical calls. Reference is the name of a node under test, or an extra
+// NAME=URL node that is sent each distinct call once after the load test. By
+// default every node is compared to the first one by name.
+type VerificationParameters struct {
+	Reference  string `json:"reference,omitempty"`
+	MaxSamples int    `json:"max_samples,omitempty"` // mismatches kept per method and node
+}
+
+// ResponseMismatch is a call whose normalized result differs between a node
+// and the reference
+type ResponseMismatch struct {
+	Node              string          `json:"node"`
+	Request           *JsonrpcMessage `json:"request"`
+	Response          json.RawMessage `json:"response"`
+	ReferenceResponse json.RawMessage `json:"reference_response"`
+}
+
+// MethodVerification counts the calls of a method compared to the reference.
+// Calls are skipped when either response is missing or failed, or when they
+// depend on the chain head.
+type MethodVerification struct {
+	Method     string             `json:"method"`
+	Compared   int                `json:"compared"`
+	Mismatched int                `json:"mismatched"`
+	Skipped    int                `json:"skipped"`
+	Mismatches []ResponseMismatch `json:"mismatches"`
+}
+
+type VerificationReport struct {
+	Reference string               `json:"reference"`
+	Nodes     []string             `json:"nodes"`
+	Methods   []MethodVerification `json:"methods"`
+}
+
+// Mismatched is the total number of mismatched calls
+func (r *VerificationReport) Mismatched() int {
+	total := 0
+	for _, method := range r.Methods {
+		total += method.Mismatched
+	}
+	return total
+}
@@ -0,0 +1,23 @@
+package types
+
+// WorkloadMethod is a single JSON-RPC method of a mixed workload and its
+// relative share of the generated calls.
+type WorkloadMethod struct {
+	Method string          `json:"method"`
+	Weight float64         `json:"weight"`
+	Params *WorkloadParams `json:"params,omitempty"`
+}
+
+// WorkloadParams pin the inputs of the calls of a method instead of drawing
+// them from the sample datasets. Lists are cycled through to fill the calls.
+type WorkloadParams struct {
+	Addresses        []string `json:"addresses,omitempty"`
+	Holders          []string `json:"holders,omitempty"`
+	Transactions     []string `json:"transactions,omitempty"`
+	Blocks           []int64  `json:"blocks,omitempty"`
+	LogsBlockRange   int64    `json:"logs_block_range,omitempty"`
+	FullTransactions bool     `json:"full_transactions,omitempty"`
+}
+
+// Workload is a weighted mix of JSON-RPC methods.
+type Workload []WorkloadMethod
@@ -0,0 +1,28 @@
+package utils
+
+import "strings"
+
+// SplitArgs splits comma-separated arguments, leaving the commas of bracketed
+// arrays such as [a,b] within their argument. An empty string has no arguments.
+func SplitArgs(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+
+	args := []string{}
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(args, strings.TrimSpace(s[start:]))
+}
@@ -14,7 +14,10 @@ import (
 	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
 )
 
-func BatchSendRawTransactions(batches [][]*types.Transaction, url string) ([]string, error) {
+// BatchSendRawTransactions sends the batches of transactions one after the
+// other, calling sent, if not nil, with the number of transactions that the
+// node accepted from each batch
+func BatchSendRawTransactions(batches [][]*types.Transaction, url string, sent func(n int)) ([]string, error) {
 
 	log.Info().Msg("Sending transactions in batches...")
 
@@ -62,6 +65,7 @@ func BatchSendRawTransactions(batches [][]*types.Transaction, url string) ([]str
 		}
 		// log.Info().Msgf("responses: %s", responses)
 
+		accepted := 0
 		for _, response := range responses {
 			if err, ok := response["error"]; ok {
 				log.Info().Msgf("error: %s", err)
@@ -69,8 +73,12 @@ func BatchSendRawTransactions(batches [][]*types.Transaction, url string) ([]str
 				batchErrors = append(batchErrors, fmt.Sprintf("%v", err))
 			} else if result, ok := response["result"].(string); ok {
 				txHashes = append(txHashes, result)
+				accepted++
 			}
 		}
+		if sent != nil {
+			sent(accepted)
+		}
 
 		bar.Add(1)
 	}
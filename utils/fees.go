@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// NewFeeTransaction builds an unsigned transaction of the type of the fee
+// mode. The access list only goes into access list transactions.
+func NewFeeTransaction(chainID *big.Int, fees *tooltypes.TxFees, nonce uint64, to common.Address, value *big.Int, gas uint64, data []byte, accessList types.AccessList) *types.Transaction {
+	switch fees.Mode {
+	case tooltypes.DynamicFees:
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			To:        &to,
+			Value:     value,
+			Gas:       gas,
+			GasTipCap: fees.GasTipCap,
+			GasFeeCap: fees.GasFeeCap,
+			Data:      data,
+		})
+	case tooltypes.AccessListFees:
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      nonce,
+			To:         &to,
+			Value:      value,
+			Gas:        gas,
+			GasPrice:   fees.GasPrice,
+			Data:       data,
+			AccessList: accessList,
+		})
+	default:
+		return types.NewTransaction(nonce, to, value, gas, fees.GasPrice, data)
+	}
+}
+
+// ApplyFees prices the transactions sent through a transactor, such as
+// contract deployments and fundings, with the given fees
+func ApplyFees(opts *bind.TransactOpts, fees *tooltypes.TxFees) {
+	if fees.Mode == tooltypes.DynamicFees {
+		opts.GasPrice = nil
+		opts.GasTipCap = fees.GasTipCap
+		opts.GasFeeCap = fees.GasFeeCap
+		return
+	}
+	opts.GasPrice = fees.GasPrice
+	opts.GasTipCap = nil
+	opts.GasFeeCap = nil
+}
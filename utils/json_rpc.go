@@ -1,15 +1,20 @@
 package utils
 
 import (
+	"sync/atomic"
+
 	"github.com/unifralabs/unifra-benchmark-tool/types"
-	"golang.org/x/exp/rand"
 )
 
+// jsonrpcID is the last id assigned to a JSON-RPC message, so that every
+// call generated by this process can be matched back from its response.
+var jsonrpcID atomic.Int64
+
 func NewJsonrpcMessage(method string, parameters []interface{}) *types.JsonrpcMessage {
 	return &types.JsonrpcMessage{
 		Version: "2.0",
 		Method:  method,
 		Params:  parameters,
-		ID:      rand.Int63n(1e18) + 1, // Generate a random ID between 1 and 1e18
+		ID:      jsonrpcID.Add(1),
 	}
 }
@@ -18,7 +18,7 @@ func SignTransactions(ethclient *ethclient.Client, accounts []*tooltypes.SenderA
 	bar := progressbar.Default(int64(len(transactions)))
 	signedTxs := make([]*types.Transaction, 0, len(transactions))
 
-	chainID, err := ethclient.NetworkID(context.Background())
+	chainID, err := ethclient.ChainID(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +26,8 @@ func SignTransactions(ethclient *ethclient.Client, accounts []*tooltypes.SenderA
 	for i, tx := range transactions {
 		sender := accounts[i%len(accounts)]
 
-		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), sender.PrivateKey)
+		// The Prague signer signs every transaction type, up to set code transactions
+		signedTx, err := types.SignTx(tx, types.NewPragueSigner(chainID), sender.PrivateKey)
 		if err != nil {
 			failedTxnSignErrors = append(failedTxnSignErrors, err)
 			continue
@@ -2,8 +2,10 @@ package utils
 
 import (
 	"fmt"
+	"math"
 	"os"
-	"strconv"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -64,90 +66,148 @@ func GetNodesPlotColors(nodes map[string]tooltypes.Node) map[string]string {
 	return colors
 }
 
+// PrintMetricTables prints one table per metric with a row per target rate
+// and a column per result. Each result maps "target_rate" to []int and each
+// metric to []float64. Results are aligned by target rate, so results run at
+// different rates can be compared. names orders the columns, defaulting to
+// the sorted result names. In comparison mode, every result after the first
+// gets a ratio and a percent delta column against the first one.
 func PrintMetricTables(
 	results map[string]interface{},
+	names []string,
 	metrics []string,
 	suffix string,
 	decimals *int,
 	comparison *bool,
 	indent *int,
 ) {
+	useIndent := 0
+	if indent != nil {
+		useIndent = *indent
+	}
+	pad := strings.Repeat(" ", useIndent)
+
 	if len(results) == 0 {
-		log.Info().Msg(strings.Repeat(" ", *indent) + "no results")
+		log.Info().Msg(pad + "no results")
 		return
 	}
 
-	if comparison == nil {
-		comp := len(results) == 2
-		comparison = &comp
+	if names == nil {
+		for name := range results {
+			names = append(names, name)
+		}
+		sort.Strings(names)
 	}
 
-	names := make([]string, 0, len(results))
-	for name := range results {
-		names = append(names, name)
+	if comparison == nil {
+		comp := len(names) == 2
+		comparison = &comp
 	}
 
+	// Results run at the same rates are aligned row by row, which keeps
+	// repeated rates (e.g. spike tests) apart. Otherwise rows are the union
+	// of the rates of all results.
 	rates := results[names[0]].(map[string]interface{})["target_rate"].([]int)
+	sameRates := true
+	for _, name := range names[1:] {
+		if !slices.Equal(rates, results[name].(map[string]interface{})["target_rate"].([]int)) {
+			sameRates = false
+		}
+	}
+	if !sameRates {
+		rateSet := make(map[int]bool)
+		for _, name := range names {
+			for _, rate := range results[name].(map[string]interface{})["target_rate"].([]int) {
+				rateSet[rate] = true
+			}
+		}
+		rates = make([]int, 0, len(rateSet))
+		for rate := range rateSet {
+			rates = append(rates, rate)
+		}
+		sort.Ints(rates)
+	}
 
 	for _, metric := range metrics {
 		var metricSuffix string
 		switch metric {
 		case "success", "n_invalid_json_errors", "n_rpc_errors":
 			metricSuffix = ""
-		case "throughput":
+		case "throughput", "actual_rate":
 			metricSuffix = " (rps)"
 		default:
 			metricSuffix = " (s)"
 		}
 
-		unittedNames := make([]string, len(names))
-		for i, name := range names {
-			unittedNames[i] = name + metricSuffix
+		// values[i][j] is the value of names[j] at rates[i]
+		values := make([][]float64, len(rates))
+		all := []float64{}
+		for i := range values {
+			values[i] = make([]float64, len(names))
 		}
-
-		labels := append([]string{"rate (rps)"}, unittedNames...)
-		if *comparison {
-			if len(results) != 2 {
-				panic("comparison of >2 tests not implemented")
+		for j, name := range names {
+			result := results[name].(map[string]interface{})
+			resultRates := result["target_rate"].([]int)
+			metricValues := result[metric].([]float64)
+			for i, rate := range rates {
+				values[i][j] = math.NaN()
+				if sameRates {
+					values[i][j] = metricValues[i]
+				} else if k := slices.Index(resultRates, rate); k >= 0 {
+					values[i][j] = metricValues[k]
+				}
+				if !math.IsNaN(values[i][j]) {
+					all = append(all, values[i][j])
+				}
 			}
-			labels = append(labels, names[0]+" / "+names[1])
 		}
 
-		rows := make([][]string, len(rates))
-		for i, rate := range rates {
-			row := make([]string, 1, len(labels))
-			row[0] = fmt.Sprintf("%d", rate)
-			rows[i] = row
+		useDecimals := 6
+		if decimals != nil {
+			useDecimals = *decimals
+		} else if allGreaterThanOne(all) {
+			useDecimals = 1
 		}
-
-		values := make([]float64, 0)
-		for _, name := range names {
-			metricValues := results[name].(map[string]interface{})[metric].([]float64)
-			for i, value := range metricValues {
-				rows[i] = append(rows[i], fmt.Sprintf("%.6f", value))
-				values = append(values, value)
+		formatValue := func(value float64, format string) string {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return "-"
 			}
+			return fmt.Sprintf(format, value)
 		}
 
+		labels := []string{"rate (rps)"}
+		for _, name := range names {
+			labels = append(labels, name+metricSuffix)
+		}
 		if *comparison {
-			for i := range rows {
-				v1, _ := strconv.ParseFloat(rows[i][1], 64)
-				v2, _ := strconv.ParseFloat(rows[i][2], 64)
-				rows[i] = append(rows[i], fmt.Sprintf("%.1f%%", v1/v2*100))
+			for _, name := range names[1:] {
+				labels = append(labels, name+" / "+names[0], name+" Δ%")
 			}
 		}
 
-		// useDecimals := 6
-		// if decimals != nil {
-		// 	useDecimals = *decimals
-		// } else if allGreaterThanOne(values) {
-		// 	useDecimals = 1
-		// }
+		rows := make([][]string, len(rates))
+		for i, rate := range rates {
+			row := []string{fmt.Sprintf("%d", rate)}
+			for j := range names {
+				row = append(row, formatValue(values[i][j], fmt.Sprintf("%%.%df", useDecimals)))
+			}
+			if *comparison {
+				base := values[i][0]
+				for j := 1; j < len(names); j++ {
+					row = append(row,
+						formatValue(values[i][j]/base, "%.3fx"),
+						formatValue((values[i][j]-base)/base*100, "%+.1f%%"),
+					)
+				}
+			}
+			rows[i] = row
+		}
 
 		// Print header
-		log.Info().Msg(strings.Repeat(" ", *indent) + "+" + strings.Repeat("-", len(metric+" vs load"+suffix)+2) + "+")
-		log.Info().Msg(strings.Repeat(" ", *indent) + "| " + color.New(color.FgHiWhite, color.Bold).Sprint(metric+" vs load"+suffix) + " |")
-		log.Info().Msg(strings.Repeat(" ", *indent) + "+" + strings.Repeat("-", len(metric+" vs load"+suffix)+2) + "+")
+		title := metric + " vs load" + suffix
+		log.Info().Msg(pad + "+" + strings.Repeat("-", len(title)+2) + "+")
+		log.Info().Msg(pad + "| " + color.New(color.FgHiWhite, color.Bold).Sprint(title) + " |")
+		log.Info().Msg(pad + "+" + strings.Repeat("-", len(title)+2) + "+")
 
 		// Print table
 		table := tablewriter.NewWriter(os.Stdout)
@@ -160,9 +220,6 @@ func PrintMetricTables(
 		table.SetColumnAlignment(alignment)
 		table.AppendBulk(rows)
 		table.Render()
-
-		if metric != metrics[len(metrics)-1] {
-		}
 	}
 }
 
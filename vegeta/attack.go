@@ -0,0 +1,252 @@
+package vegeta
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWorkers is the default initial number of workers used to carry an attack.
+	DefaultWorkers uint64 = 10
+	// DefaultMaxWorkers is the default maximum number of workers used to carry an attack.
+	DefaultMaxWorkers uint64 = 1 << 32
+	// DefaultConnections is the default amount of max open idle connections per target host.
+	DefaultConnections = 10000
+	// DefaultMaxConnections is the default amount of connections per target host (0 = unlimited).
+	DefaultMaxConnections = 0
+	// DefaultTimeout is the default amount of time an attacker waits for a request.
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxBody is the default max number of bytes read from a response body (-1 = unlimited).
+	DefaultMaxBody = int64(-1)
+)
+
+// Target is a single HTTP request sent by an Attacker.
+type Target struct {
+	Method string
+	URL    string
+	Body   []byte
+	Header http.Header
+}
+
+// Request builds the http.Request for the target.
+func (t *Target) Request(ctx context.Context) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, t.Method, t.URL, bytes.NewReader(t.Body))
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range t.Header {
+		req.Header[k] = append(req.Header[k], vs...)
+	}
+	return req, nil
+}
+
+// Attacker is an in-process HTTP load generator that hits targets at a
+// constant rate, in the same way as the `vegeta attack` command.
+type Attacker struct {
+	client     *http.Client
+	workers    uint64
+	maxWorkers uint64
+	maxBody    int64
+	onResult   func(*Result)
+}
+
+// NewAttacker returns a new Attacker with default options, overridden by the given ones.
+func NewAttacker(opts ...func(*Attacker)) *Attacker {
+	a := &Attacker{
+		workers:    DefaultWorkers,
+		maxWorkers: DefaultMaxWorkers,
+		maxBody:    DefaultMaxBody,
+	}
+	a.client = &http.Client{
+		Timeout: DefaultTimeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   DefaultTimeout,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSClientConfig:     &tls.Config{},
+			MaxIdleConnsPerHost: DefaultConnections,
+			MaxConnsPerHost:     DefaultMaxConnections,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// Workers returns an option which sets the initial number of workers of an Attacker.
+func Workers(n uint64) func(*Attacker) {
+	return func(a *Attacker) { a.workers = n }
+}
+
+// MaxWorkers returns an option which sets the maximum number of workers of an Attacker.
+func MaxWorkers(n uint64) func(*Attacker) {
+	return func(a *Attacker) { a.maxWorkers = n }
+}
+
+// Connections returns an option which sets the number of idle open connections per host.
+func Connections(n int) func(*Attacker) {
+	return func(a *Attacker) {
+		if tr, ok := a.client.Transport.(*http.Transport); ok {
+			tr.MaxIdleConnsPerHost = n
+		}
+	}
+}
+
+// MaxConnections returns an option which sets the number of connections per host.
+func MaxConnections(n int) func(*Attacker) {
+	return func(a *Attacker) {
+		if tr, ok := a.client.Transport.(*http.Transport); ok {
+			tr.MaxConnsPerHost = n
+		}
+	}
+}
+
+// Timeout returns an option which sets the timeout of each request.
+func Timeout(d time.Duration) func(*Attacker) {
+	return func(a *Attacker) { a.client.Timeout = d }
+}
+
+// MaxBody returns an option which limits the number of bytes read from each response body.
+func MaxBody(n int64) func(*Attacker) {
+	return func(a *Attacker) { a.maxBody = n }
+}
+
+// Client returns an option which replaces the http.Client used by an Attacker.
+func Client(c *http.Client) func(*Attacker) {
+	return func(a *Attacker) { a.client = c }
+}
+
+// OnResult returns an option which calls fn with the result of each hit, as
+// soon as the hit completes. fn is called concurrently by the workers.
+func OnResult(fn func(*Result)) func(*Attacker) {
+	return func(a *Attacker) { a.onResult = fn }
+}
+
+// Attack sends rate requests per second for the given duration, cycling
+// through the targets in order. It blocks until every in-flight request has
+// completed and returns the results ordered by sequence number.
+func (a *Attacker) Attack(ctx context.Context, targets []Target, rate int, duration time.Duration, name string) []Result {
+	if len(targets) == 0 || rate <= 0 || duration <= 0 {
+		return []Result{}
+	}
+
+	hits := uint64(rate) * uint64(duration) / uint64(time.Second)
+	interval := time.Second / time.Duration(rate)
+
+	results := make([]Result, hits)
+	ticks := make(chan uint64)
+
+	var wg sync.WaitGroup
+	workers := uint64(0)
+	spawn := func() {
+		wg.Add(1)
+		workers++
+		go func() {
+			defer wg.Done()
+			for seq := range ticks {
+				results[seq] = a.hit(ctx, &targets[seq%uint64(len(targets))], name, seq)
+				if a.onResult != nil {
+					a.onResult(&results[seq])
+				}
+			}
+		}()
+	}
+	for workers < a.workers && workers < hits {
+		spawn()
+	}
+
+	began := time.Now()
+	sent := uint64(0)
+loop:
+	for ; sent < hits; sent++ {
+		if wait := time.Until(began.Add(time.Duration(sent) * interval)); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				break loop
+			}
+		}
+
+		select {
+		case ticks <- sent:
+			continue
+		case <-ctx.Done():
+			break loop
+		default:
+		}
+
+		// All workers are busy, spawn another one if allowed
+		if workers < a.maxWorkers {
+			spawn()
+		}
+		select {
+		case ticks <- sent:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+
+	close(ticks)
+	wg.Wait()
+
+	return results[:sent]
+}
+
+func (a *Attacker) hit(ctx context.Context, tr *Target, name string, seq uint64) (res Result) {
+	res = Result{
+		Attack:    name,
+		Seq:       seq,
+		Timestamp: time.Now(),
+		Method:    tr.Method,
+		URL:       tr.URL,
+		BytesOut:  uint64(len(tr.Body)),
+	}
+	defer func() {
+		res.Latency = time.Since(res.Timestamp)
+	}()
+
+	req, err := tr.Request(ctx)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	r, err := a.client.Do(req)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	defer r.Body.Close()
+
+	body := io.Reader(r.Body)
+	if a.maxBody >= 0 {
+		body = io.LimitReader(r.Body, a.maxBody)
+	}
+
+	if res.Body, err = io.ReadAll(body); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	// Drain the remainder so the connection can be reused
+	io.Copy(io.Discard, r.Body)
+
+	res.BytesIn = uint64(len(res.Body))
+	res.Code = uint16(r.StatusCode)
+	res.Headers = r.Header
+	if res.Code < 200 || res.Code >= 400 {
+		res.Error = r.Status
+	}
+
+	return res
+}
@@ -0,0 +1,146 @@
+package vegeta
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyServer answers every request with its body after delay, and
+// records the highest number of requests it served at once
+type concurrencyServer struct {
+	*httptest.Server
+	inFlight    atomic.Int64
+	maxInFlight atomic.Int64
+}
+
+func newConcurrencyServer(t *testing.T, delay time.Duration) *concurrencyServer {
+	s := &concurrencyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		for {
+			highest := s.maxInFlight.Load()
+			if n <= highest || s.maxInFlight.CompareAndSwap(highest, n) {
+				break
+			}
+		}
+
+		time.Sleep(delay)
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func testTargets(url string, n int) []Target {
+	targets := make([]Target, n)
+	for i := range targets {
+		targets[i] = Target{Method: http.MethodPost, URL: url, Body: []byte(fmt.Sprintf(`{"id":%d}`, i))}
+	}
+	return targets
+}
+
+func TestAttackHitsRateTimesDuration(t *testing.T) {
+	server := newConcurrencyServer(t, 0)
+	targets := testTargets(server.URL, 3)
+
+	results := NewAttacker().Attack(context.Background(), targets, 40, 500*time.Millisecond, "test")
+	if len(results) != 20 {
+		t.Fatalf("got %d hits, want 20", len(results))
+	}
+
+	for i, res := range results {
+		if res.Seq != uint64(i) {
+			t.Fatalf("result %d has seq %d, want results ordered by seq", i, res.Seq)
+		}
+		target := targets[i%len(targets)]
+		if res.Attack != "test" || res.Method != target.Method || res.URL != target.URL {
+			t.Errorf("result %d: got attack %q %s %s, want test %s %s", i, res.Attack, res.Method, res.URL, target.Method, target.URL)
+		}
+		if res.Code != http.StatusOK || res.Error != "" {
+			t.Errorf("result %d: got code %d and error %q, want 200 and no error", i, res.Code, res.Error)
+		}
+		if string(res.Body) != string(target.Body) {
+			t.Errorf("result %d: got body %s, want %s", i, res.Body, target.Body)
+		}
+		if res.BytesIn != uint64(len(target.Body)) || res.BytesOut != uint64(len(target.Body)) {
+			t.Errorf("result %d: got %d bytes in and %d out, want %d", i, res.BytesIn, res.BytesOut, len(target.Body))
+		}
+		if res.Latency <= 0 || res.Timestamp.IsZero() {
+			t.Errorf("result %d: got latency %v at %v, want a positive latency and a timestamp", i, res.Latency, res.Timestamp)
+		}
+	}
+}
+
+func TestAttackMaxWorkers(t *testing.T) {
+	server := newConcurrencyServer(t, 50*time.Millisecond)
+
+	attacker := NewAttacker(Workers(1), MaxWorkers(2))
+	results := attacker.Attack(context.Background(), testTargets(server.URL, 1), 100, 200*time.Millisecond, "test")
+	if len(results) != 20 {
+		t.Fatalf("got %d hits, want 20", len(results))
+	}
+	if got := server.maxInFlight.Load(); got > 2 {
+		t.Errorf("server saw %d concurrent requests, want at most 2 workers", got)
+	}
+}
+
+func TestAttackMaxConnections(t *testing.T) {
+	server := newConcurrencyServer(t, 50*time.Millisecond)
+
+	attacker := NewAttacker(MaxConnections(1))
+	results := attacker.Attack(context.Background(), testTargets(server.URL, 1), 100, 100*time.Millisecond, "test")
+	if len(results) != 10 {
+		t.Fatalf("got %d hits, want 10", len(results))
+	}
+	if got := server.maxInFlight.Load(); got != 1 {
+		t.Errorf("server saw %d concurrent requests, want 1 over a single connection", got)
+	}
+	for i, res := range results {
+		if res.Error != "" {
+			t.Errorf("result %d: unexpected error %q", i, res.Error)
+		}
+	}
+}
+
+func TestAttackTimeout(t *testing.T) {
+	server := newConcurrencyServer(t, 200*time.Millisecond)
+
+	results := NewAttacker(Timeout(20*time.Millisecond)).Attack(context.Background(), testTargets(server.URL, 1), 10, 200*time.Millisecond, "test")
+	if len(results) != 2 {
+		t.Fatalf("got %d hits, want 2", len(results))
+	}
+	for i, res := range results {
+		if res.Error == "" || res.Code != 0 {
+			t.Errorf("result %d: got code %d and error %q, want a timeout error", i, res.Code, res.Error)
+		}
+		if res.Latency >= 200*time.Millisecond {
+			t.Errorf("result %d: got latency %v, want the request cut at the timeout", i, res.Latency)
+		}
+	}
+}
+
+func TestAttackMaxBody(t *testing.T) {
+	server := newConcurrencyServer(t, 0)
+	targets := []Target{{Method: http.MethodPost, URL: server.URL, Body: []byte("0123456789")}}
+
+	results := NewAttacker(MaxBody(4)).Attack(context.Background(), targets, 10, 200*time.Millisecond, "test")
+	if len(results) != 2 {
+		t.Fatalf("got %d hits, want 2", len(results))
+	}
+	for i, res := range results {
+		if string(res.Body) != "0123" || res.BytesIn != 4 {
+			t.Errorf("result %d: got body %q of %d bytes, want it truncated to 0123", i, res.Body, res.BytesIn)
+		}
+		if res.Code != http.StatusOK || res.Error != "" {
+			t.Errorf("result %d: got code %d and error %q, want 200 and no error", i, res.Code, res.Error)
+		}
+	}
+}
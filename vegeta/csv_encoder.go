@@ -0,0 +1,61 @@
+package vegeta
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// An Encoder encodes a Result and returns an error in case of failure.
+type Encoder func(*Result) error
+
+// NewCSVEncoder returns an Encoder that writes Results in the CSV format
+// read by NewCSVDecoder, one record per line.
+func NewCSVEncoder(w io.Writer) Encoder {
+	enc := csv.NewWriter(w)
+
+	return func(r *Result) error {
+		var headers bytes.Buffer
+		if r.Headers != nil {
+			if err := r.Headers.Write(&headers); err != nil {
+				return err
+			}
+			headers.WriteString("\r\n")
+		}
+
+		err := enc.Write([]string{
+			strconv.FormatInt(r.Timestamp.UnixNano(), 10),
+			strconv.FormatUint(uint64(r.Code), 10),
+			strconv.FormatInt(r.Latency.Nanoseconds(), 10),
+			strconv.FormatUint(r.BytesOut, 10),
+			strconv.FormatUint(r.BytesIn, 10),
+			r.Error,
+			base64.StdEncoding.EncodeToString(r.Body),
+			r.Attack,
+			strconv.FormatUint(r.Seq, 10),
+			r.Method,
+			r.URL,
+			base64.StdEncoding.EncodeToString(headers.Bytes()),
+		})
+		if err != nil {
+			return err
+		}
+
+		enc.Flush()
+		return enc.Error()
+	}
+}
+
+// EncodeResults encodes all results into CSV.
+func EncodeResults(results []Result) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := NewCSVEncoder(&buf)
+	for i := range results {
+		if err := enc(&results[i]); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,17 @@
+package vegeta
+
+import "io"
+
+// NewResultsDecoder returns a Decoder that streams Results from memory,
+// returning io.EOF once all of them have been decoded.
+func NewResultsDecoder(results []Result) Decoder {
+	i := 0
+	return func(r *Result) error {
+		if i >= len(results) {
+			return io.EOF
+		}
+		*r = results[i]
+		i++
+		return nil
+	}
+}
@@ -1,170 +1,143 @@
 package vegeta
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/base64"
-	"encoding/csv"
-	"encoding/json"
-	"fmt"
-	"os/exec"
+	"io"
+	"math"
+	"slices"
+	"sort"
 	"strconv"
-	"strings"
+	"time"
 
-	"github.com/rs/zerolog/log"
 	"github.com/unifralabs/unifra-benchmark-tool/types"
 	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
 	"github.com/unifralabs/unifra-benchmark-tool/utils"
 )
 
-func ComputeDeepDatum(rawOutput []byte, targetRate int, targetDuration int, calls []*types.JsonrpcMessage) (map[tooltypes.ResponseCategory]tooltypes.LoadTestDeepOutputDatum, []tooltypes.ErrorPair, error) {
-	allDF, err := convertRawVegetaOutputToDataframe(rawOutput)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	rpcError := make([]bool, len(allDF))
-	invalidJSONError := make([]bool, len(allDF))
-
-	for i, row := range allDF {
-		statusCode := row["status_code"].(int64)
-		response := row["response"].(string)
-
-		if statusCode == 200 {
-			decoded, err := base64.StdEncoding.DecodeString(response)
-			if err != nil {
-				invalidJSONError[i] = true
-				continue
-			}
-
-			var result map[string]interface{}
-			if err := json.Unmarshal(decoded, &result); err != nil {
-				invalidJSONError[i] = true
-				continue
-			}
-
-			if result["result"] == nil {
-				rpcError[i] = true
-			}
-		}
-	}
-
-	allDF = append(allDF, map[string]interface{}{
-		"invalid_json_error": invalidJSONError,
-		"rpc_error":          rpcError,
-	})
-
-	deepSuccess := make([]bool, len(allDF))
-	for i, row := range allDF {
-		deepSuccess[i] = row["status_code"] != nil && row["status_code"].(int64) == 200 &&
-			row["invalid_json_error"] == nil &&
-			row["rpc_error"] == nil
-	}
-	allDF = append(allDF, map[string]interface{}{"deep_success": deepSuccess})
+// DeepDatum holds the metrics computed from the individual responses of an attack
+type DeepDatum struct {
+	Metrics       map[tooltypes.ResponseCategory]tooltypes.LoadTestDeepOutputDatum
+	MethodMetrics map[string]tooltypes.LoadTestDeepOutputDatum
+	ErrorPairs    []tooltypes.ErrorPair
+	ErrorGroups   []tooltypes.RpcErrorGroup
+}
 
-	rpcErrorPairs, err := gatherErrorPairs(allDF, calls)
+func ComputeDeepDatum(dec Decoder, targetRate int, targetDuration int, calls []*types.JsonrpcMessage) (*DeepDatum, error) {
+	allRecords, err := DecodeResponseRecords(dec, calls)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	return computeDeepDatum(allRecords, targetRate, targetDuration)
+}
 
+func computeDeepDatum(allRecords []*ResponseRecord, targetRate int, targetDuration int) (*DeepDatum, error) {
 	categoryData := make(map[tooltypes.ResponseCategory]tooltypes.LoadTestDeepOutputDatum)
-	dataframes := []struct {
+	categories := []struct {
 		category tooltypes.ResponseCategory
-		df       []map[string]interface{}
+		records  []*ResponseRecord
 	}{
-		{"all", allDF},
-		{"successful", filterDataframe(allDF, func(row map[string]interface{}) bool { return row["deep_success"].(bool) })},
-		{"failed", filterDataframe(allDF, func(row map[string]interface{}) bool { return !row["deep_success"].(bool) })},
+		{tooltypes.AllResponses, allRecords},
+		{tooltypes.SuccessfulResponses, filterRecords(allRecords, func(r *ResponseRecord) bool { return r.DeepSuccess() })},
+		{tooltypes.FailedResponses, filterRecords(allRecords, func(r *ResponseRecord) bool { return !r.DeepSuccess() })},
+	}
+
+	for _, c := range categories {
+		metrics, err := computeRawOutputSampleMetrics(c.records, targetRate, targetDuration)
+		if err != nil {
+			return nil, err
+		}
+		categoryData[c.category] = metrics
 	}
 
-	for _, df := range dataframes {
-		metrics, err := computeRawOutputSampleMetrics(df.df, targetRate, targetDuration)
+	// Break the metrics down per method, each method being targeted at its share of the rate
+	methodData := make(map[string]tooltypes.LoadTestDeepOutputDatum)
+	for method, records := range groupRecordsByMethod(allRecords) {
+		methodRate := targetRate
+		if len(allRecords) > 0 {
+			methodRate = int(math.Round(float64(targetRate) * float64(len(records)) / float64(len(allRecords))))
+		}
+		metrics, err := computeRawOutputSampleMetrics(records, methodRate, targetDuration)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
-		categoryData[df.category] = metrics
+		methodData[method] = metrics
 	}
 
-	return categoryData, rpcErrorPairs, nil
+	return &DeepDatum{
+		Metrics:       categoryData,
+		MethodMetrics: methodData,
+		ErrorPairs:    gatherErrorPairs(allRecords),
+		ErrorGroups:   groupErrors(allRecords),
+	}, nil
 }
 
-func convertRawVegetaOutputToDataframe(rawOutput []byte) ([]map[string]interface{}, error) {
-	cmd := exec.Command("vegeta", "encode", "--to", "csv")
-	cmd.Stdin = strings.NewReader(string(rawOutput))
-	reportOutput, err := cmd.Output()
-	if err != nil {
-		return nil, err
+func groupRecordsByMethod(records []*ResponseRecord) map[string][]*ResponseRecord {
+	groups := make(map[string][]*ResponseRecord)
+	for _, record := range records {
+		groups[record.RpcMethod] = append(groups[record.RpcMethod], record)
 	}
+	return groups
+}
 
-	// results := make([]Result, 0)
-	// dec := NewCSVDecoder(strings.NewReader(string(reportOutput)))
-	// var r Result
-	// for {
-	// 	err = dec(&r)
-	// 	if err != nil {
-	// 		break
-	// 	}
-	// 	results = append(results, r)
-	// }
-	// log.Info().Msgf("results: %s", results)
-
-	reader := csv.NewReader(strings.NewReader(string(reportOutput)))
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, err
+// gatherErrorPairs pairs each JSON-RPC error response with the request that caused it
+func gatherErrorPairs(records []*ResponseRecord) []tooltypes.ErrorPair {
+	var pairs []tooltypes.ErrorPair
+	for _, record := range records {
+		if !record.RpcError {
+			continue
+		}
+		var rpcError interface{} = record.RpcErrorDetail
+		if record.RpcErrorDetail == nil {
+			rpcError = string(record.Response)
+		}
+		pairs = append(pairs, tooltypes.ErrorPair{record.Call, rpcError})
 	}
 
-	// log.Info().Msgf("records: %s", records)
+	return pairs
+}
 
-	schema := []string{
-		"timestamp", "status_code", "latency", "bytes_out", "bytes_in",
-		"error", "response", "name", "index", "method", "url", "response_headers",
+// groupErrors counts the JSON-RPC errors by method and error code
+func groupErrors(records []*ResponseRecord) []tooltypes.RpcErrorGroup {
+	type groupKey struct {
+		method string
+		code   int
+		coded  bool
 	}
 
-	var df []map[string]interface{}
+	groups := []tooltypes.RpcErrorGroup{}
+	indexes := map[groupKey]int{}
 	for _, record := range records {
-		row := make(map[string]interface{})
-		for i, field := range record {
-			switch schema[i] {
-			case "timestamp", "status_code", "latency", "bytes_out", "bytes_in", "index":
-				value, _ := strconv.ParseInt(field, 10, 64)
-				row[schema[i]] = value
-			default:
-				row[schema[i]] = field
-			}
+		if !record.RpcError {
+			continue
 		}
-		log.Info().Msgf("row: %s", row)
-		df = append(df, row)
-	}
-
-	return df, nil
-}
 
-func gatherErrorPairs(df []map[string]interface{}, calls []*types.JsonrpcMessage) ([]tooltypes.ErrorPair, error) {
-	callsByID := make(map[int64]interface{})
-	for _, call := range calls {
-		// callMap := call.(map[string]interface{})
-		// callID, ok := callMap["id"].(string)
-		// if !ok {
-		// 	return nil, fmt.Errorf("id not specified for call")
-		// }
-		callID := call.ID
-		if _, exists := callsByID[callID]; exists {
-			return nil, fmt.Errorf("duplicate call for id")
+		key := groupKey{method: record.RpcMethod}
+		if record.RpcErrorCode != nil {
+			key.code = *record.RpcErrorCode
+			key.coded = true
 		}
-		callsByID[callID] = call
-	}
 
-	var pairs []tooltypes.ErrorPair
-	for _, row := range df {
-		if row["rpc_error"] != nil {
-			pairs = append(pairs, tooltypes.ErrorPair{nil, row["response"].(string)})
+		index, ok := indexes[key]
+		if !ok {
+			index = len(groups)
+			indexes[key] = index
+			groups = append(groups, tooltypes.RpcErrorGroup{
+				Method:  record.RpcMethod,
+				Code:    record.RpcErrorCode,
+				Message: record.RpcErrorMessage,
+			})
 		}
+		groups[index].Count++
 	}
 
-	return pairs, nil
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Count > groups[j].Count })
+	return groups
 }
 
-func computeRawOutputSampleMetrics(df []map[string]interface{}, targetRate int, targetDuration int) (tooltypes.LoadTestDeepOutputDatum, error) {
-	if len(df) == 0 {
+func computeRawOutputSampleMetrics(records []*ResponseRecord, targetRate int, targetDuration int) (tooltypes.LoadTestDeepOutputDatum, error) {
+	if len(records) == 0 {
 		return tooltypes.LoadTestDeepOutputDatum{
 			TargetRate:         targetRate,
 			ActualRate:         utils.NewFloat64(0),
@@ -187,28 +160,90 @@ func computeRawOutputSampleMetrics(df []map[string]interface{}, targetRate int,
 		}, nil
 	}
 
-	// Compute metrics...
-	// This part would require significant work to replicate the exact functionality of the Python code
-	// As Go doesn't have an exact equivalent to Polars, you might need to use a different data processing library
-	// or implement the calculations manually
+	var (
+		firstRequest, lastRequest, lastResponse time.Time
+		totalLatency                            time.Duration
+		nSuccess, nInvalidJSON, nRPCErrors      int
+	)
+	statusCodes := map[string]int{}
+	errors := []string{}
+	seenErrors := map[string]bool{}
+	latencies := make([]time.Duration, 0, len(records))
+
+	for i, record := range records {
+		if i == 0 || record.Timestamp.Before(firstRequest) {
+			firstRequest = record.Timestamp
+		}
+		if record.Timestamp.After(lastRequest) {
+			lastRequest = record.Timestamp
+		}
+		if end := record.Timestamp.Add(record.Latency); end.After(lastResponse) {
+			lastResponse = end
+		}
+
+		latencies = append(latencies, record.Latency)
+		totalLatency += record.Latency
+
+		statusCodes[strconv.Itoa(int(record.StatusCode))]++
+		if record.Error != "" && !seenErrors[record.Error] {
+			seenErrors[record.Error] = true
+			errors = append(errors, record.Error)
+		}
+
+		if record.DeepSuccess() {
+			nSuccess++
+		}
+		if record.InvalidJSON {
+			nInvalidJSON++
+		}
+		if record.RpcError {
+			nRPCErrors++
+		}
+	}
+
+	slices.Sort(latencies)
+
+	n := float64(len(records))
+	actualDuration := lastRequest.Sub(firstRequest).Seconds()
+	finalWaitTime := lastResponse.Sub(lastRequest).Seconds()
+
+	var actualRate, throughput *float64
+	if actualDuration > 0 {
+		actualRate = utils.NewFloat64(n / actualDuration)
+	}
+	if actualDuration+finalWaitTime > 0 {
+		throughput = utils.NewFloat64(float64(nSuccess) / (actualDuration + finalWaitTime))
+	}
 
-	// For demonstration, I'll just return a placeholder struct
 	return tooltypes.LoadTestDeepOutputDatum{
-		TargetRate:     targetRate,
-		ActualRate:     utils.NewFloat64(float64(len(df)) / float64(targetDuration)),
-		TargetDuration: targetDuration,
-		// ... other fields would be computed here
+		TargetRate:            targetRate,
+		ActualRate:            actualRate,
+		TargetDuration:        targetDuration,
+		ActualDuration:        utils.NewFloat64(actualDuration),
+		Requests:              len(records),
+		Throughput:            throughput,
+		Success:               utils.NewFloat64(float64(nSuccess) / n),
+		Min:                   utils.NewFloat64(latencies[0].Seconds()),
+		Mean:                  utils.NewFloat64(totalLatency.Seconds() / n),
+		P50:                   utils.NewFloat64(Quantile(latencies, 0.50).Seconds()),
+		P90:                   utils.NewFloat64(Quantile(latencies, 0.90).Seconds()),
+		P95:                   utils.NewFloat64(Quantile(latencies, 0.95).Seconds()),
+		P99:                   utils.NewFloat64(Quantile(latencies, 0.99).Seconds()),
+		Max:                   utils.NewFloat64(latencies[len(latencies)-1].Seconds()),
+		StatusCodes:           statusCodes,
+		Errors:                errors,
+		FirstRequestTimestamp: formatTimestamp(firstRequest),
+		LastRequestTimestamp:  formatTimestamp(lastRequest),
+		LastResponseTimestamp: formatTimestamp(lastResponse),
+		FinalWaitTime:         utils.NewFloat64(finalWaitTime),
+		NInvalidJSONErrors:    nInvalidJSON,
+		NRPCErrors:            nRPCErrors,
 	}, nil
 }
 
-func filterDataframe(df []map[string]interface{}, predicate func(map[string]interface{}) bool) []map[string]interface{} {
-	var result []map[string]interface{}
-	for _, row := range df {
-		if predicate(row) {
-			result = append(result, row)
-		}
-	}
-	return result
+func formatTimestamp(t time.Time) *string {
+	formatted := t.Format(time.RFC3339Nano)
+	return &formatted
 }
 
 func EncodeRawVegetaOutput(rawOutput []byte) string {
@@ -224,14 +259,26 @@ func DecodeRawVegetaOutput(encodedOutput string) ([]byte, error) {
 	return decompressGzip(decoded)
 }
 
+// compressGzip compresses data, a write to memory that cannot fail
 func compressGzip(data []byte) []byte {
-	// Implement gzip compression
-	// This is a placeholder and needs to be implemented
-	return data
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(data)
+	gz.Close()
+	return buf.Bytes()
 }
 
+// decompressGzip decompresses data. Raw outputs saved before they were
+// compressed are returned as they are.
 func decompressGzip(data []byte) ([]byte, error) {
-	// Implement gzip decompression
-	// This is a placeholder and needs to be implemented
-	return data, nil
+	if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		return data, nil
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
 }
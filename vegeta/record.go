@@ -0,0 +1,159 @@
+package vegeta
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// ResponseRecord is the typed outcome of a single JSON-RPC request of an attack.
+type ResponseRecord struct {
+	Seq        uint64
+	Timestamp  time.Time
+	StatusCode uint16
+	Latency    time.Duration
+	BytesOut   uint64
+	BytesIn    uint64
+	Error      string
+	Response   []byte
+
+	// JSON-RPC fields, decoded from the call and the response body
+	Call            *tooltypes.JsonrpcMessage
+	RpcID           int64
+	RpcMethod       string
+	RpcErrorDetail  *tooltypes.JsonError
+	RpcErrorCode    *int
+	RpcErrorMessage string
+	InvalidJSON     bool
+	RpcError        bool
+}
+
+// DeepSuccess reports whether the request succeeded at both the HTTP and the JSON-RPC level.
+func (r *ResponseRecord) DeepSuccess() bool {
+	return r.StatusCode == 200 && !r.InvalidJSON && !r.RpcError
+}
+
+// ErrorCode classifies a failed request: by its JSON-RPC error code, its HTTP
+// status, invalid_json, empty_result, or transport when no response came
+// back. It is empty for successful requests.
+func (r *ResponseRecord) ErrorCode() string {
+	switch {
+	case r.StatusCode == 0:
+		return "transport"
+	case r.StatusCode != 200:
+		return strconv.Itoa(int(r.StatusCode))
+	case r.InvalidJSON:
+		return "invalid_json"
+	case r.RpcErrorCode != nil:
+		return strconv.Itoa(*r.RpcErrorCode)
+	case r.RpcError:
+		return "empty_result"
+	}
+	return ""
+}
+
+// DecodeResponseRecords reads every Result from dec and converts it into a
+// ResponseRecord. Each response is matched to its call by JSON-RPC id; when
+// the response carries no usable id, the call is found from the sequence
+// number since attacks cycle through their calls in order.
+func DecodeResponseRecords(dec Decoder, calls []*tooltypes.JsonrpcMessage) ([]*ResponseRecord, error) {
+	callsByID, err := indexCallsByID(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []*ResponseRecord{}
+	for {
+		var r Result
+		if err := dec(&r); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		records = append(records, newResponseRecord(&r, calls, callsByID))
+	}
+	return records, nil
+}
+
+// DecodeRawResponseRecords decodes the records of an attack from its deep raw output
+func DecodeRawResponseRecords(encodedOutput string, calls []*tooltypes.JsonrpcMessage) ([]*ResponseRecord, error) {
+	rawOutput, err := DecodeRawVegetaOutput(encodedOutput)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeResponseRecords(NewCSVDecoder(bytes.NewReader(rawOutput)), calls)
+}
+
+func indexCallsByID(calls []*tooltypes.JsonrpcMessage) (map[int64]*tooltypes.JsonrpcMessage, error) {
+	callsByID := make(map[int64]*tooltypes.JsonrpcMessage, len(calls))
+	for _, call := range calls {
+		if _, exists := callsByID[call.ID]; exists {
+			return nil, fmt.Errorf("duplicate call for id %d", call.ID)
+		}
+		callsByID[call.ID] = call
+	}
+	return callsByID, nil
+}
+
+func newResponseRecord(r *Result, calls []*tooltypes.JsonrpcMessage, callsByID map[int64]*tooltypes.JsonrpcMessage) *ResponseRecord {
+	record := &ResponseRecord{
+		Seq:        r.Seq,
+		Timestamp:  r.Timestamp,
+		StatusCode: r.Code,
+		Latency:    r.Latency,
+		BytesOut:   r.BytesOut,
+		BytesIn:    r.BytesIn,
+		Error:      r.Error,
+		Response:   r.Body,
+	}
+
+	if len(calls) > 0 {
+		record.setCall(calls[r.Seq%uint64(len(calls))])
+	}
+
+	if r.Code != 200 {
+		return record
+	}
+
+	var response tooltypes.JsonrpcMessage
+	if err := json.Unmarshal(r.Body, &response); err != nil {
+		record.InvalidJSON = true
+		return record
+	}
+	if call, ok := callsByID[response.ID]; ok {
+		record.setCall(call)
+	}
+	if response.Error != nil {
+		record.RpcError = true
+		record.RpcErrorDetail = response.Error
+		record.RpcErrorCode = &response.Error.Code
+		record.RpcErrorMessage = response.Error.Message
+	} else if len(response.Result) == 0 || string(response.Result) == "null" {
+		record.RpcError = true
+	}
+
+	return record
+}
+
+func (r *ResponseRecord) setCall(call *tooltypes.JsonrpcMessage) {
+	r.Call = call
+	r.RpcID = call.ID
+	r.RpcMethod = call.Method
+}
+
+func filterRecords(records []*ResponseRecord, predicate func(*ResponseRecord) bool) []*ResponseRecord {
+	result := []*ResponseRecord{}
+	for _, record := range records {
+		if predicate(record) {
+			result = append(result, record)
+		}
+	}
+	return result
+}
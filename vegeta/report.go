@@ -0,0 +1,111 @@
+package vegeta
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// ComputeReport aggregates attack results into the same shape as the output
+// of `vegeta report -type json`. Latencies, durations and waits are expressed
+// in nanoseconds.
+func ComputeReport(results []Result) tooltypes.RawLoadTestOutputDatum {
+	report := tooltypes.RawLoadTestOutputDatum{
+		Latencies:   map[string]float64{},
+		BytesIn:     map[string]float64{},
+		BytesOut:    map[string]float64{},
+		StatusCodes: map[string]int{},
+		Errors:      []string{},
+	}
+	if len(results) == 0 {
+		return report
+	}
+
+	var (
+		earliest, latest, end time.Time
+		totalLatency          time.Duration
+		totalBytesIn          uint64
+		totalBytesOut         uint64
+		success               int
+		seenErrors            = map[string]bool{}
+	)
+
+	latencies := make([]time.Duration, 0, len(results))
+	for _, r := range results {
+		if earliest.IsZero() || r.Timestamp.Before(earliest) {
+			earliest = r.Timestamp
+		}
+		if r.Timestamp.After(latest) {
+			latest = r.Timestamp
+		}
+		if e := r.Timestamp.Add(r.Latency); e.After(end) {
+			end = e
+		}
+
+		latencies = append(latencies, r.Latency)
+		totalLatency += r.Latency
+		totalBytesIn += r.BytesIn
+		totalBytesOut += r.BytesOut
+
+		report.StatusCodes[strconv.Itoa(int(r.Code))]++
+		if r.Code >= 200 && r.Code < 400 {
+			success++
+		}
+		if r.Error != "" && !seenErrors[r.Error] {
+			seenErrors[r.Error] = true
+			report.Errors = append(report.Errors, r.Error)
+		}
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	n := float64(len(results))
+	report.Requests = len(results)
+	report.Latencies["total"] = float64(totalLatency)
+	report.Latencies["mean"] = float64(totalLatency) / n
+	report.Latencies["min"] = float64(latencies[0])
+	report.Latencies["50th"] = float64(Quantile(latencies, 0.50))
+	report.Latencies["90th"] = float64(Quantile(latencies, 0.90))
+	report.Latencies["95th"] = float64(Quantile(latencies, 0.95))
+	report.Latencies["99th"] = float64(Quantile(latencies, 0.99))
+	report.Latencies["max"] = float64(latencies[len(latencies)-1])
+	report.BytesIn["total"] = float64(totalBytesIn)
+	report.BytesIn["mean"] = float64(totalBytesIn) / n
+	report.BytesOut["total"] = float64(totalBytesOut)
+	report.BytesOut["mean"] = float64(totalBytesOut) / n
+
+	duration := latest.Sub(earliest)
+	wait := end.Sub(latest)
+	report.Earliest = earliest.Format(time.RFC3339Nano)
+	report.Latest = latest.Format(time.RFC3339Nano)
+	report.End = end.Format(time.RFC3339Nano)
+	report.Duration = int(duration)
+	report.Wait = int(wait)
+	report.Success = float64(success) / n
+	if duration > 0 {
+		report.Rate = n / duration.Seconds()
+	}
+	if total := (duration + wait).Seconds(); total > 0 {
+		report.Throughput = float64(success) / total
+	}
+
+	return report
+}
+
+// Quantile returns the nearest-rank quantile q of an ascending sorted slice of latencies.
+func Quantile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
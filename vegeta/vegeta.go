@@ -1,133 +1,177 @@
 package vegeta
 
 import (
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/unifralabs/unifra-benchmark-tool/metrics"
 	"github.com/unifralabs/unifra-benchmark-tool/types"
 	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
 	"github.com/unifralabs/unifra-benchmark-tool/utils"
 )
 
-func RunVegetaAttack(url string, rate int, calls []*types.JsonrpcMessage, duration int, vegetaArgs *string, verbose bool, includeDeepOutput []tooltypes.DeepOutput) (*tooltypes.LoadTestOutputDatum, error) {
-	attack, err := constructVegetaAttack(calls, url, nil, verbose)
+func RunVegetaAttack(node tooltypes.Node, rate int, calls []*types.JsonrpcMessage, duration int, vegetaArgs *string, verbose bool, includeDeepOutput []tooltypes.DeepOutput) (*tooltypes.LoadTestOutputDatum, error) {
+	targets, err := constructVegetaTargets(calls, node.URL)
 	if err != nil {
 		return nil, err
 	}
 
-	attackOutput, err := vegetaAttack(attack["schedule_dir"], &duration, &rate, nil, nil, nil, nil, vegetaArgs, verbose)
+	opts, err := parseVegetaArgs(vegetaArgs)
 	if err != nil {
 		return nil, err
 	}
 
-	report, err := createVegetaReport(attackOutput, rate, duration, includeDeepOutput, calls)
-	if err != nil {
-		return nil, err
+	// Without the raw output, each response is decoded as soon as it completes
+	// and its body dropped, so that long attacks don't hold every body
+	raw := slices.Contains(includeDeepOutput, tooltypes.RawDeepOutput)
+	var records []*ResponseRecord
+	if !raw && slices.Contains(includeDeepOutput, tooltypes.MetricsDeepOutput) {
+		records = make([]*ResponseRecord, max(rate*duration, 0))
 	}
-
-	return report, nil
-}
-
-func constructVegetaAttack(calls []*types.JsonrpcMessage, url string, scheduleDir *string, verbose bool) (map[string]string, error) {
-	headers := map[string]string{"Content-Type": "application/json"}
-
-	if scheduleDir == nil {
-		tempDir, err := ioutil.TempDir("", "vegeta_attack")
-		if err != nil {
-			return nil, err
-		}
-		scheduleDir = &tempDir
+	observe := metrics.Enabled()
+	if observe {
+		metrics.SetRpcTargetRate(node.Name, rate)
 	}
-
-	callPaths := []string{}
-	for c, call := range calls {
-		vegetaCallsPath := filepath.Join(*scheduleDir, fmt.Sprintf("vegeta_calls_%d.json", c))
-		callJSON, err := json.Marshal(call)
-		if err != nil {
+	if observe || !raw {
+		callsByID, err := indexCallsByID(calls)
+		if err != nil && records != nil {
 			return nil, err
 		}
-		if err := ioutil.WriteFile(vegetaCallsPath, callJSON, 0644); err != nil {
-			return nil, err
-		}
-		callPaths = append(callPaths, vegetaCallsPath)
+		opts = append(opts, OnResult(func(r *Result) {
+			if observe || records != nil {
+				record := newResponseRecord(r, calls, callsByID)
+				if observe {
+					metrics.ObserveRpcRequest(node.Name, record.RpcMethod, record.Latency, record.ErrorCode())
+				}
+				if records != nil && r.Seq < uint64(len(records)) {
+					// Only the errors without details are reported with their response
+					if !record.RpcError || record.RpcErrorDetail != nil {
+						record.Response = nil
+					}
+					records[r.Seq] = record
+				}
+			}
+			if !raw {
+				r.Body = nil
+			}
+		}))
 	}
 
-	vegetaTargetsPath := filepath.Join(*scheduleDir, "vegeta_targets")
-	f, err := os.Create(vegetaTargetsPath)
-	if err != nil {
-		return nil, err
+	if verbose {
+		log.Info().Msg("running vegeta attack...")
+		log.Info().Msgf("- url: %s", node.URL)
+		log.Info().Msgf("- targets: %d", len(targets))
+		if vegetaArgs != nil {
+			log.Info().Msgf("- args: %s", *vegetaArgs)
+		}
 	}
-	defer f.Close()
 
-	for c, _ := range calls {
-		fmt.Fprintf(f, "POST %s\n", url)
-		for key, value := range headers {
-			fmt.Fprintf(f, "%s: %s\n", key, value)
-		}
-		fmt.Fprintf(f, "@%s\n", callPaths[c])
+	attacker := NewAttacker(opts...)
+	results := attacker.Attack(context.Background(), targets, rate, time.Duration(duration)*time.Second, "")
+	if records != nil {
+		records = records[:len(results)]
 	}
 
-	if verbose {
-		log.Info().Msg("running vegeta attack...")
-		log.Info().Msgf("- targets: %s", vegetaTargetsPath)
-		log.Info().Msgf("- calls: %s", callPaths[len(callPaths)-1])
+	report, err := createVegetaReport(results, records, rate, duration, includeDeepOutput, calls)
+	if err != nil {
+		return nil, err
 	}
 
-	return map[string]string{
-		"schedule_dir":        *scheduleDir,
-		"vegeta_calls_path":   callPaths[len(callPaths)-1],
-		"vegeta_targets_path": vegetaTargetsPath,
-	}, nil
+	return report, nil
 }
 
-func vegetaAttack(scheduleDir string, duration *int, rate *int, maxConnections *int, maxWorkers *int, nCpus *int, reportPath *string, vegetaArgs *string, verbose bool) ([]byte, error) {
-	log.Info().Msg("running vegeta attack...")
-	cmd := []string{"vegeta", "attack"}
-	cmd = append(cmd, "-targets="+filepath.Join(scheduleDir, "vegeta_targets"))
-
-	if rate != nil {
-		cmd = append(cmd, fmt.Sprintf("-rate=%d", *rate))
-	}
-	if duration != nil {
-		cmd = append(cmd, fmt.Sprintf("-duration=%ds", *duration))
-	}
-	if maxConnections != nil {
-		cmd = append(cmd, fmt.Sprintf("-max-connections=%d", *maxConnections))
+// FetchResponses sends each call once to url, at the given rate, and returns
+// the results in the order of the calls
+func FetchResponses(url string, calls []*types.JsonrpcMessage, rate int) ([]Result, error) {
+	targets, err := constructVegetaTargets(calls, url)
+	if err != nil {
+		return nil, err
 	}
-	if maxWorkers != nil {
-		cmd = append(cmd, fmt.Sprintf("-max-workers=%d", *maxWorkers))
+	if rate <= 0 {
+		return nil, fmt.Errorf("rate must be positive")
 	}
-	if vegetaArgs != nil {
-		cmd = append(cmd, strings.Split(*vegetaArgs, " ")...)
+
+	// Round the duration up so that the attack sends exactly one hit per call
+	duration := (time.Duration(len(targets))*time.Second + time.Duration(rate) - 1) / time.Duration(rate)
+	results := NewAttacker().Attack(context.Background(), targets, rate, duration, "")
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("sent %d of %d calls", len(results), len(calls))
 	}
+	return results, nil
+}
 
-	if verbose {
-		log.Info().Msgf("- command: %s", strings.Join(cmd, " "))
+func constructVegetaTargets(calls []*types.JsonrpcMessage, url string) ([]Target, error) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+
+	targets := make([]Target, len(calls))
+	for c, call := range calls {
+		callJSON, err := json.Marshal(call)
+		if err != nil {
+			return nil, err
+		}
+		targets[c] = Target{
+			Method: http.MethodPost,
+			URL:    url,
+			Body:   callJSON,
+			Header: header,
+		}
 	}
 
-	return exec.Command(cmd[0], cmd[1:]...).Output()
+	return targets, nil
 }
 
-func createVegetaReport(attackOutput []byte, targetRate int, targetDuration int, includeDeepOutput []tooltypes.DeepOutput, calls []*types.JsonrpcMessage) (*tooltypes.LoadTestOutputDatum, error) {
-	cmd := exec.Command("vegeta", "report", "-type", "json")
-	cmd.Stdin = strings.NewReader(string(attackOutput))
-	reportOutput, err := cmd.Output()
-	if err != nil {
-		return nil, err
-	}
+// parseVegetaArgs translates the subset of `vegeta attack` flags that the
+// in-process Attacker supports into attacker options.
+func parseVegetaArgs(vegetaArgs *string) ([]func(*Attacker), error) {
+	opts := []func(*Attacker){}
+	if vegetaArgs == nil || strings.TrimSpace(*vegetaArgs) == "" {
+		return opts, nil
+	}
+
+	fs := flag.NewFlagSet("vegeta", flag.ContinueOnError)
+	workers := fs.Uint64("workers", DefaultWorkers, "initial number of workers")
+	maxWorkers := fs.Uint64("max-workers", DefaultMaxWorkers, "maximum number of workers")
+	connections := fs.Int("connections", DefaultConnections, "max open idle connections per target host")
+	maxConnections := fs.Int("max-connections", DefaultMaxConnections, "max connections per target host")
+	timeout := fs.Duration("timeout", DefaultTimeout, "requests timeout")
+	maxBody := fs.Int64("max-body", DefaultMaxBody, "maximum number of bytes to capture from response bodies")
+
+	if err := fs.Parse(strings.Fields(*vegetaArgs)); err != nil {
+		return nil, fmt.Errorf("invalid vegeta args %q: %w", *vegetaArgs, err)
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "workers":
+			opts = append(opts, Workers(*workers))
+		case "max-workers":
+			opts = append(opts, MaxWorkers(*maxWorkers))
+		case "connections":
+			opts = append(opts, Connections(*connections))
+		case "max-connections":
+			opts = append(opts, MaxConnections(*maxConnections))
+		case "timeout":
+			opts = append(opts, Timeout(*timeout))
+		case "max-body":
+			opts = append(opts, MaxBody(*maxBody))
+		}
+	})
 
-	log.Info().Msgf("reportOutput: %s", string(reportOutput))
+	return opts, nil
+}
 
-	var report tooltypes.RawLoadTestOutputDatum
-	if err := json.Unmarshal(reportOutput, &report); err != nil {
-		return nil, err
-	}
+// createVegetaReport summarizes the results of an attack. The deep metrics
+// come from the records decoded during the attack if any, else from the results.
+func createVegetaReport(results []Result, records []*ResponseRecord, targetRate int, targetDuration int, includeDeepOutput []tooltypes.DeepOutput, calls []*types.JsonrpcMessage) (*tooltypes.LoadTestOutputDatum, error) {
+	report := ComputeReport(results)
 
 	var latencyMin *float64
 	if min, ok := report.Latencies["min"]; ok {
@@ -137,7 +181,9 @@ func createVegetaReport(attackOutput []byte, targetRate int, targetDuration int,
 
 	var deepRawOutput *string
 	var deepMetrics map[tooltypes.ResponseCategory]tooltypes.LoadTestDeepOutputDatum
+	var deepMethodMetrics map[string]tooltypes.LoadTestDeepOutputDatum
 	var deepRpcErrorPairs []tooltypes.ErrorPair
+	var deepRpcErrorGroups []tooltypes.RpcErrorGroup
 
 	if includeDeepOutput == nil {
 		includeDeepOutput = []tooltypes.DeepOutput{}
@@ -146,14 +192,27 @@ func createVegetaReport(attackOutput []byte, targetRate int, targetDuration int,
 	for _, output := range includeDeepOutput {
 		switch output {
 		case "raw":
+			attackOutput, err := EncodeResults(results)
+			if err != nil {
+				return nil, err
+			}
 			encodedOutput := EncodeRawVegetaOutput(attackOutput)
 			deepRawOutput = &encodedOutput
 		case "metrics":
+			var deepDatum *DeepDatum
 			var err error
-			deepMetrics, deepRpcErrorPairs, err = ComputeDeepDatum(attackOutput, targetRate, targetDuration, calls)
+			if records != nil {
+				deepDatum, err = computeDeepDatum(records, targetRate, targetDuration)
+			} else {
+				deepDatum, err = ComputeDeepDatum(NewResultsDecoder(results), targetRate, targetDuration, calls)
+			}
 			if err != nil {
 				return nil, err
 			}
+			deepMetrics = deepDatum.Metrics
+			deepMethodMetrics = deepDatum.MethodMetrics
+			deepRpcErrorPairs = deepDatum.ErrorPairs
+			deepRpcErrorGroups = deepDatum.ErrorGroups
 		}
 	}
 
@@ -181,5 +240,7 @@ func createVegetaReport(attackOutput []byte, targetRate int, targetDuration int,
 		DeepRawOutput:         deepRawOutput,
 		DeepMetrics:           deepMetrics,
 		DeepRPCErrorPairs:     deepRpcErrorPairs,
+		DeepRPCErrorGroups:    deepRpcErrorGroups,
+		DeepMethodMetrics:     deepMethodMetrics,
 	}, nil
 }
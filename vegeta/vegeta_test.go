@@ -0,0 +1,95 @@
+package vegeta
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tooltypes "github.com/unifralabs/unifra-benchmark-tool/types"
+)
+
+// newJsonrpcServer answers the calls of odd ids with a result, the calls of
+// ids divisible by 4 with a null result and the other calls with an error
+func newJsonrpcServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var call tooltypes.JsonrpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch {
+		case call.ID%2 == 1:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":"0x1"}`, call.ID)
+		case call.ID%4 == 0:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":null}`, call.ID)
+		default:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32000,"message":"failed"}}`, call.ID)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRunVegetaAttackDeepMetricsWithoutBodies(t *testing.T) {
+	server := newJsonrpcServer(t)
+	node := tooltypes.Node{Name: "test", URL: server.URL}
+	calls := make([]*tooltypes.JsonrpcMessage, 8)
+	for i := range calls {
+		calls[i] = &tooltypes.JsonrpcMessage{Version: "2.0", ID: int64(i + 1), Method: "eth_blockNumber"}
+	}
+
+	withRaw, err := RunVegetaAttack(node, 40, calls, 1, nil, false, []tooltypes.DeepOutput{tooltypes.MetricsDeepOutput, tooltypes.RawDeepOutput})
+	if err != nil {
+		t.Fatalf("attack with raw output: %v", err)
+	}
+	withoutRaw, err := RunVegetaAttack(node, 40, calls, 1, nil, false, []tooltypes.DeepOutput{tooltypes.MetricsDeepOutput})
+	if err != nil {
+		t.Fatalf("attack without raw output: %v", err)
+	}
+
+	if withRaw.DeepRawOutput == nil || withoutRaw.DeepRawOutput != nil {
+		t.Fatalf("raw output kept = %t and %t, want only with the raw deep output", withRaw.DeepRawOutput != nil, withoutRaw.DeepRawOutput != nil)
+	}
+	for _, category := range []tooltypes.ResponseCategory{tooltypes.AllResponses, tooltypes.SuccessfulResponses, tooltypes.FailedResponses} {
+		want, got := withRaw.DeepMetrics[category], withoutRaw.DeepMetrics[category]
+		if got.Requests != want.Requests || got.NRPCErrors != want.NRPCErrors || got.NInvalidJSONErrors != want.NInvalidJSONErrors {
+			t.Errorf("%s: got %d requests, %d RPC and %d JSON errors, want %d, %d and %d", category,
+				got.Requests, got.NRPCErrors, got.NInvalidJSONErrors, want.Requests, want.NRPCErrors, want.NInvalidJSONErrors)
+		}
+	}
+	if all := withoutRaw.DeepMetrics[tooltypes.AllResponses]; all.Requests != 40 || all.NRPCErrors != 20 {
+		t.Errorf("got %d requests and %d RPC errors, want 40 and 20", all.Requests, all.NRPCErrors)
+	}
+
+	if len(withoutRaw.DeepRPCErrorPairs) != len(withRaw.DeepRPCErrorPairs) {
+		t.Fatalf("got %d error pairs, want %d", len(withoutRaw.DeepRPCErrorPairs), len(withRaw.DeepRPCErrorPairs))
+	}
+	got, _ := json.Marshal(withoutRaw.DeepRPCErrorPairs)
+	want, _ := json.Marshal(withRaw.DeepRPCErrorPairs)
+	if string(got) != string(want) {
+		t.Errorf("got error pairs %s, want %s", got, want)
+	}
+}
+
+func TestRawVegetaOutputRoundTrip(t *testing.T) {
+	rawOutput := []byte(strings.Repeat("1700000000000000000,200,1000,10,20,,eyJpZCI6MX0=,,0,\n", 100))
+
+	encoded := EncodeRawVegetaOutput(rawOutput)
+	if len(encoded) >= len(base64.StdEncoding.EncodeToString(rawOutput)) {
+		t.Errorf("encoded output of %d bytes is not compressed", len(encoded))
+	}
+	decoded, err := DecodeRawVegetaOutput(encoded)
+	if err != nil || string(decoded) != string(rawOutput) {
+		t.Errorf("DecodeRawVegetaOutput = %q, %v, want the raw output", decoded, err)
+	}
+
+	// Raw outputs saved before compression still decode
+	decoded, err = DecodeRawVegetaOutput(base64.StdEncoding.EncodeToString(rawOutput))
+	if err != nil || string(decoded) != string(rawOutput) {
+		t.Errorf("DecodeRawVegetaOutput of an uncompressed output = %q, %v, want the raw output", decoded, err)
+	}
+}